@@ -0,0 +1,125 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/platform"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyCmd = &cobra.Command{
+		Use:   "apply",
+		Short: "Apply for the queued jobs",
+		Long: `Applies for the jobs queued by "jb collect", best scoring and most recent
+first, until the queue is empty or the daily limit is reached. Each job
+leaves the queue marked as submitted or with the reason it failed.
+
+Jobs that failed can be put back in the queue with --requeue.`,
+		Args: cobra.NoArgs,
+		RunE: apply,
+	}
+	applyList    bool
+	applyRequeue bool
+)
+
+// requeueStatuses are the failed application outcomes put back in the queue by --requeue.
+var requeueStatuses = []string{
+	datastore.StatusFailed,
+	datastore.StatusNeedsHuman,
+}
+
+func init() {
+	applyCmd.Flags().BoolVarP(&applyList, "list", "l", false, "list the queued jobs without applying")
+	applyCmd.Flags().BoolVar(&applyRequeue, "requeue", false, "put failed jobs back in the queue first")
+}
+
+func apply(cmd *cobra.Command, _ []string) error {
+	if applyRequeue {
+		if err := requeueFailed(cmd); err != nil {
+			return err
+		}
+	}
+
+	if applyList {
+		return listQueue(cmd)
+	}
+
+	return runBot(cmd.Context(), (*platform.Runner).Apply)
+}
+
+func requeueFailed(cmd *cobra.Command) error {
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	n, err := ds.RequeueJobPostings(cmd.Context(), requeueStatuses...)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job postings. %w", err)
+	}
+	log.Info().Int("count", n).Msg("Failed jobs requeued")
+
+	return nil
+}
+
+func listQueue(cmd *cobra.Command) error {
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	return printQueue(cmd.Context(), ds)
+}
+
+// printQueue prints the queued jobs in the order they are applied for.
+func printQueue(ctx context.Context, ds datastore.Datastore) error {
+	posts, err := ds.GetUnappliedJobPostings(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCORE\tPOSTED\tTITLE\tCOMPANY\tURL")
+	for _, post := range posts {
+		posted := "-"
+		if !post.PostedAt.IsZero() {
+			posted = post.PostedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%g\t%s\t%s\t%s\t%s\n", post.Score, posted, post.Title, post.Company, post.Url)
+	}
+
+	return w.Flush()
+}
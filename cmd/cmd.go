@@ -27,6 +27,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -52,6 +54,12 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $HOME/.jb.yaml)")
 	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(collectCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(questionsCmd)
+	rootCmd.AddCommand(filterCmd)
 }
 
 func initConfig() {
@@ -80,3 +88,27 @@ func initConfig() {
 
 	fmt.Println("Config file used for jb: ", viper.ConfigFileUsed())
 }
+
+// loadConfig decodes the config file read by initConfig, rejecting unknown keys.
+func loadConfig() (config.Config, error) {
+	var file struct {
+		config.Config `mapstructure:",squash"`
+		// Path is the --config flag, which init binds to viper
+		Path string `mapstructure:"config"`
+	}
+	if err := viper.UnmarshalExact(&file); err != nil {
+		return file.Config, fmt.Errorf("failed to parse config. %w", err)
+	}
+
+	return file.Config, nil
+}
+
+// openDatastore opens the datastore selected by the config file.
+func openDatastore() (datastore.Datastore, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return datastore.New(cfg.Datastore)
+}
@@ -0,0 +1,51 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"github.com/k1ng440/job-bot/internal/platform"
+	"github.com/spf13/cobra"
+)
+
+var collectCmd = &cobra.Command{
+	Use:   "collect",
+	Short: "Search for jobs and queue the matching ones",
+	Long: `Searches every search url and stores the jobs passing the filters in the
+queue without applying for them. Review the queue with "jb apply --list"
+and apply for it with "jb apply".
+
+With --dry-run the jobs are collected into memory and printed, leaving the
+database untouched.`,
+	Args: cobra.NoArgs,
+	RunE: collect,
+}
+
+func init() {
+	collectCmd.Flags().BoolVar(&resetCheckpoints, "reset-checkpoints", false, "start every search from the first page")
+	collectCmd.Flags().BoolVar(&dryRun, "dry-run", false, "collect into memory and print the queue")
+}
+
+func collect(cmd *cobra.Command, _ []string) error {
+	return runBot(cmd.Context(), (*platform.Runner).Collect)
+}
@@ -0,0 +1,106 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbCmd = &cobra.Command{
+		Use:   "db",
+		Short: "Manage the database",
+		Long: `The database schema is versioned. Pending migrations run automatically
+whenever the database is opened. A sqlite database file is backed up
+before migrating it.`,
+	}
+	dbMigrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Run the pending database migrations",
+		Args:  cobra.NoArgs,
+		RunE:  migrateDB,
+	}
+	dbStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "List the database migrations and whether they ran",
+		Args:  cobra.NoArgs,
+		RunE:  showDBStatus,
+	}
+)
+
+func init() {
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+}
+
+func migrateDB(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	migrations, err := datastore.Migrate(cmd.Context(), cfg.Datastore)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to migrate database")
+		return err
+	}
+
+	if len(migrations) == 0 {
+		log.Info().Msg("Database is up to date")
+		return nil
+	}
+	log.Info().Int("version", migrations[len(migrations)-1].Version).Msg("Database migrated")
+
+	return nil
+}
+
+func showDBStatus(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	migrations, err := datastore.MigrationStatus(cmd.Context(), cfg.Datastore)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tDESCRIPTION\tAPPLIED")
+	for _, m := range migrations {
+		applied := "pending"
+		if !m.AppliedAt.IsZero() {
+			applied = m.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Description, applied)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,274 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/filter"
+	"github.com/k1ng440/job-bot/internal/indeed"
+	"github.com/k1ng440/job-bot/internal/linkedin"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filterCmd = &cobra.Command{
+		Use:   "filter",
+		Short: "Work with the job posting filters",
+	}
+	filterTestCmd = &cobra.Command{
+		Use:   "test",
+		Short: "Dry-run the filters against stored or saved job postings",
+		Long: `Runs the configured whitelists, blacklists, languages, filter expressions
+and scoring rules against job postings and prints whether each one would be
+applied to, along with the rule that rejected it.
+
+Postings come from the datastore unless --fixture is given. A fixture is
+either a JSON file holding a list of postings:
+
+  [{"title": "...", "company": "...", "description": "...", "location": "...",
+    "seniority": "...", "workplace_type": "remote", "employment_type": "full-time",
+    "applicant_count": 42, "salary": "...", "posted_at": "2023-08-01T00:00:00Z",
+    "search": "..."}]
+
+or a job page of the platform saved as HTML, which is read with a headless
+browser. The id of a saved indeed job is taken from the file name.
+
+Postings are checked with the filters of the search that found them, so
+the blacklists and languages of a linkedin search apply on top of the global
+ones. Fixture postings name their search with "search".
+
+Stored postings without a description are checked without the description
+and language rules, which is noted next to the result.
+
+The linkedin filters and postings are used unless --platform names another
+platform.`,
+		Args: cobra.NoArgs,
+		RunE: testFilter,
+	}
+	filterFixture  string
+	filterVerbose  bool
+	filterPlatform string
+)
+
+func init() {
+	filterTestCmd.Flags().StringVarP(&filterFixture, "fixture", "f", "", "JSON or HTML file with job postings to test against")
+	filterTestCmd.Flags().BoolVarP(&filterVerbose, "verbose", "v", false, "print the score of each matching scoring rule")
+	filterTestCmd.Flags().StringVarP(&filterPlatform, "platform", "p", linkedin.Name, "platform whose filters to test: linkedin or indeed")
+	filterCmd.AddCommand(filterTestCmd)
+}
+
+// fixturePosting is a job posting in a JSON fixture file.
+type fixturePosting struct {
+	Title          string    `json:"title"`
+	Company        string    `json:"company"`
+	Description    string    `json:"description"`
+	Location       string    `json:"location"`
+	Seniority      string    `json:"seniority"`
+	WorkplaceType  string    `json:"workplace_type"`
+	EmploymentType string    `json:"employment_type"`
+	ApplicantCount int       `json:"applicant_count"`
+	Salary         string    `json:"salary"`
+	PostedAt       time.Time `json:"posted_at"`
+	Search         string    `json:"search"`
+}
+
+// postingFilters picks the posting filters of a job posting.
+type postingFilters interface {
+	Filters(post *datastore.JobPosting) *filter.PostingFilter
+}
+
+func testFilter(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// The bots build the filters of their searches without a browser
+	var filters postingFilters
+	switch filterPlatform {
+	case linkedin.Name:
+		filters, err = linkedin.New(cfg.Linkedin, nil, nil)
+	case indeed.Name:
+		filters, err = indeed.New(cfg.Indeed, nil, nil)
+	default:
+		err = fmt.Errorf("unknown platform %q, expected %s or %s", filterPlatform, linkedin.Name, indeed.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	var postings []*datastore.JobPosting
+	switch ext := strings.ToLower(filepath.Ext(filterFixture)); {
+	case filterFixture == "":
+		postings, err = storedPostings(cmd.Context(), filterPlatform)
+	case ext == ".json":
+		postings, err = jsonPostings(filterFixture)
+	case ext == ".html" || ext == ".htm":
+		postings, err = htmlPostings(cmd.Context(), filterPlatform, filterFixture)
+	default:
+		err = fmt.Errorf("unsupported fixture %q, expected a .json or .html file", filterFixture)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printFilterResults(os.Stdout, filters, postings, filterFixture == "")
+}
+
+// printFilterResults runs the postings through their filters and prints
+// whether each one would be applied to.
+func printFilterResults(out io.Writer, filters postingFilters, postings []*datastore.JobPosting, stored bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESULT\tSCORE\tTITLE\tCOMPANY\tREASON")
+	for _, post := range postings {
+		f := filters.Filters(post)
+
+		// Postings stored before descriptions were kept would fail every description rule
+		evaluate, note := f.Evaluate, ""
+		if stored && post.Description == "" {
+			evaluate, note = f.EvaluateWithoutDescription, "description not stored, description and language rules skipped"
+		}
+		res := evaluate(post)
+
+		result := "accept"
+		if !res.Allowed {
+			result = "reject"
+		}
+
+		reason := res.Reason()
+		if note != "" {
+			if reason != "" {
+				reason += "; "
+			}
+			reason += note
+		}
+		fmt.Fprintf(w, "%s\t%g\t%s\t%s\t%s\n", result, post.Score, post.Title, post.Company, reason)
+
+		if filterVerbose {
+			for _, c := range post.ScoreDetails {
+				fmt.Fprintf(w, "\t%+g\t  %s\t\t\n", c.Weight, c.Rule)
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+// storedPostings loads the job postings of the platform from the datastore.
+func storedPostings(ctx context.Context, platform string) ([]*datastore.JobPosting, error) {
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return nil, err
+	}
+	defer ds.Close()
+
+	all, err := ds.GetJobPostings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	postings := make([]*datastore.JobPosting, 0, len(all))
+	for _, post := range all {
+		if post.Platform == platform {
+			postings = append(postings, post)
+		}
+	}
+
+	return postings, nil
+}
+
+func jsonPostings(path string) ([]*datastore.JobPosting, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []fixturePosting
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %q. %w", path, err)
+	}
+
+	postings := make([]*datastore.JobPosting, 0, len(fixtures))
+	for _, f := range fixtures {
+		postings = append(postings, &datastore.JobPosting{
+			Title:          f.Title,
+			Company:        f.Company,
+			Description:    f.Description,
+			Location:       f.Location,
+			Seniority:      f.Seniority,
+			WorkplaceType:  f.WorkplaceType,
+			EmploymentType: f.EmploymentType,
+			ApplicantCount: f.ApplicantCount,
+			Salary:         f.Salary,
+			PostedAt:       f.PostedAt,
+			Search:         f.Search,
+		})
+	}
+
+	return postings, nil
+}
+
+// htmlPostings opens a saved job page of the platform in a headless browser
+// and scrapes it the same way the bot does.
+func htmlPostings(ctx context.Context, platform, path string) ([]*datastore.JobPosting, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx, chromedp.DefaultExecAllocatorOptions[:]...)
+	defer cancel()
+
+	chromedpCtx, cancel := chromedp.NewContext(allocCtx)
+	defer cancel()
+
+	if err := chromedp.Run(chromedpCtx, chromedp.Navigate("file://"+path)); err != nil {
+		return nil, fmt.Errorf("failed to open fixture %q. %w", path, err)
+	}
+
+	var post *datastore.JobPosting
+	if platform == indeed.Name {
+		post, err = indeed.ScrapeJobDetails(chromedpCtx, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	} else {
+		post, err = linkedin.ScrapeJobDetails(chromedpCtx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []*datastore.JobPosting{post}, nil
+}
@@ -0,0 +1,77 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/linkedin"
+)
+
+func TestPrintFilterResults(t *testing.T) {
+	l, err := linkedin.New(config.Linkedin{
+		PostingFilters: config.PostingFilters{
+			Blacklists: config.Patterns{Title: []string{`(?i)senior`}},
+		},
+		Searches: []config.Search{{
+			Name:       "SRE remote",
+			Keywords:   "sre",
+			Blacklists: config.Patterns{Company: []string{`(?i)acme`}},
+		}},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create linkedin bot: %v", err)
+	}
+
+	// Stored without a description, so the slow language detection is skipped
+	postings := []*datastore.JobPosting{
+		{Title: "Site Reliability Engineer", Company: "Acme"},
+		{Title: "Site Reliability Engineer", Company: "Acme", Search: "SRE remote"},
+		{Title: "Senior Site Reliability Engineer", Company: "Other", Search: "SRE remote"},
+	}
+
+	var out bytes.Buffer
+	if err := printFilterResults(&out, l, postings, true); err != nil {
+		t.Fatalf("failed to print filter results: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 results, got %q", out.String())
+	}
+	for i, expected := range []string{"accept", "reject", "reject"} {
+		if !strings.HasPrefix(lines[i+1], expected) {
+			t.Fatalf("expected posting %d to be %sed, got %q", i+1, expected, lines[i+1])
+		}
+	}
+
+	// The search blacklist adds to the global one
+	if !strings.Contains(lines[2], "company: (?i)acme") || !strings.Contains(lines[3], "title: (?i)senior") {
+		t.Fatalf("unexpected reasons in %q", out.String())
+	}
+}
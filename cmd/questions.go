@@ -0,0 +1,138 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	questionsCmd = &cobra.Command{
+		Use:   "questions",
+		Short: "List application questions without an answer",
+		Long: `Questions the answer bank could not answer are stored while applying.
+Answer them with "jb questions answer" and the jobs asking them are retried
+on the next run.`,
+		Args: cobra.NoArgs,
+		RunE: listQuestions,
+	}
+	answerQuestionCmd = &cobra.Command{
+		Use:   "answer <id> <answer>",
+		Short: "Answer a stored application question",
+		Args:  cobra.ExactArgs(2),
+		RunE:  answerQuestion,
+	}
+	listAllQuestions bool
+)
+
+func init() {
+	questionsCmd.Flags().BoolVarP(&listAllQuestions, "all", "a", false, "include answered questions")
+	questionsCmd.AddCommand(answerQuestionCmd)
+}
+
+func listQuestions(cmd *cobra.Command, _ []string) error {
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	questions, err := ds.GetQuestions(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKIND\tQUESTION\tOPTIONS\tANSWER")
+	for _, q := range questions {
+		if q.Answered && !listAllQuestions {
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", q.ID, q.Kind, q.Label, strings.Join(q.Options, " | "), q.Answer)
+	}
+
+	return w.Flush()
+}
+
+func answerQuestion(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid question id %q", args[0])
+	}
+
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	questions, err := ds.GetQuestions(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var question *datastore.Question
+	for _, q := range questions {
+		if q.ID == id {
+			question = q
+			break
+		}
+	}
+	if question == nil {
+		return fmt.Errorf("question %d not found", id)
+	}
+
+	// Store the option label so the answer matches exactly next time.
+	// Checkbox groups take a comma separated list and are left as is.
+	answer := args[1]
+	if len(question.Options) > 0 && question.Kind != "checkbox" {
+		option, ok := answers.Match(answer, question.Options)
+		if !ok {
+			return fmt.Errorf("answer must be one of: %s", strings.Join(question.Options, ", "))
+		}
+		answer = option
+	}
+
+	if err := ds.AnswerQuestion(cmd.Context(), id, answer); err != nil {
+		if errors.Is(err, datastore.ErrNotFound) {
+			return fmt.Errorf("question %d not found", id)
+		}
+		return err
+	}
+
+	fmt.Printf("Answered %q with %q\n", question.Label, answer)
+	return nil
+}
@@ -24,57 +24,181 @@ SOFTWARE.
 package cmd
 
 import (
+	"context"
+
 	"github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/answers"
 	"github.com/k1ng440/job-bot/internal/config"
 	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/indeed"
 	"github.com/k1ng440/job-bot/internal/linkedin"
+	"github.com/k1ng440/job-bot/internal/platform"
 	"github.com/k1ng440/job-bot/internal/utils"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	_ "github.com/mattn/go-sqlite3" // Import the SQLite3 driver
 )
 
-var startCmd = &cobra.Command{
-	Use:   "start",
-	Short: "Start the job bot",
-	Long:  ``,
-	RunE:  start,
+var (
+	startCmd = &cobra.Command{
+		Use:   "start",
+		Short: "Start the job bot",
+		Long: `Searches every search url of every enabled platform and applies for
+the matching jobs. The platforms run one after the other, in the order of the
+platforms setting, which defaults to linkedin. Available platforms are
+linkedin and indeed.
+
+An interrupted search resumes from where it left off unless its checkpoint
+is older than checkpoint_expiry_hours or --reset-checkpoints is given.
+
+With --dry-run the matching jobs are collected into memory and printed
+instead of applied for, leaving the database untouched.`,
+		RunE: start,
+	}
+	resetCheckpoints bool
+	dryRun           bool
+)
+
+func init() {
+	startCmd.Flags().BoolVar(&resetCheckpoints, "reset-checkpoints", false, "start every search from the first page")
+	startCmd.Flags().BoolVar(&dryRun, "dry-run", false, "collect into memory and print the queue without applying")
 }
 
 func start(cmd *cobra.Command, _ []string) error {
-	var cfg config.Config
-	viper.UnmarshalExact(&cfg)
+	return runBot(cmd.Context(), (*platform.Runner).Run)
+}
+
+// runBot sets up the datastore and the browser and runs every enabled
+// platform with run.
+func runBot(ctx context.Context, run func(*platform.Runner, context.Context) error) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
 
 	dir, destory := utils.Mkdir(cfg.ChromeProfilePath)
 	defer destory()
 
-	ds, err := datastore.NewSqliteDatastore("")
+	var ds datastore.Datastore
+	if dryRun {
+		log.Info().Msg("Dry run. Jobs are collected into memory and not applied for")
+		ds = datastore.NewMemoryDatastore()
+		run = (*platform.Runner).Collect
+	} else {
+		ds, err = datastore.New(cfg.Datastore)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create datastore")
+			return err
+		}
+	}
+	defer ds.Close()
+
+	if resetCheckpoints {
+		if err := ds.DeleteCheckpoints(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to reset checkpoints")
+			return err
+		}
+		log.Info().Msg("Checkpoints reset")
+	}
+
+	bank, err := loadAnswers(ctx, cfg.Answers, ds)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load answers")
+		return err
+	}
+
+	platforms, err := enabledPlatforms(cfg, bank, ds)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create datastore")
+		log.Error().Err(err).Msg("Failed to create platforms")
 		return err
 	}
 
-	l := linkedin.New(cfg.Linkedin, ds)
+	for _, p := range platforms {
+		log.Info().Str("platform", p.Name()).Msg("Running platform")
+		if err := runBrowser(ctx, dir, headless(cfg, p.Name()), func(ctx context.Context) error {
+			return run(platform.NewRunner(p, ds), ctx)
+		}); err != nil {
+			log.Error().Err(err).Str("platform", p.Name()).Msg("Platform exited with error")
+			return err
+		}
+	}
+
+	if dryRun {
+		return printQueue(ctx, ds)
+	}
 
+	return nil
+}
+
+// runBrowser starts a browser with the chrome profile in dir and calls run
+// with it, closing the browser once run returns.
+func runBrowser(ctx context.Context, dir string, headless bool, run func(ctx context.Context) error) error {
 	opts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.DisableGPU,
 		chromedp.UserDataDir(dir),
 		chromedp.NoSandbox,
-		chromedp.Flag("headless", cfg.Linkedin.Headless),
+		chromedp.Flag("headless", headless),
 	)
 
-	allocCtx, cancel := chromedp.NewExecAllocator(cmd.Context(), opts...)
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
 	defer cancel()
 
 	chromedpCtx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
 	defer cancel()
 
-	if err := l.Run(chromedpCtx); err != nil {
-		log.Error().Err(err).Msg("Linkedin bot exited with error")
-		return err
+	return run(chromedpCtx)
+}
+
+// headless reports whether the browser of the platform runs without a window,
+// as set in the config of the platform.
+func headless(cfg config.Config, name string) bool {
+	switch name {
+	case linkedin.Name:
+		return cfg.Linkedin.Headless
+	case indeed.Name:
+		return cfg.Indeed.Headless
+	}
+	return false
+}
+
+// enabledPlatforms creates the platforms enabled in the config.
+func enabledPlatforms(cfg config.Config, bank *answers.Bank, ds datastore.Datastore) ([]platform.Platform, error) {
+	names := cfg.Platforms
+	if len(names) == 0 {
+		names = []string{linkedin.Name}
 	}
 
-	return nil
+	platforms := make([]platform.Platform, 0, len(names))
+	for _, name := range names {
+		p, err := platform.New(name, cfg, bank, ds)
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p)
+	}
+
+	return platforms, nil
+}
+
+// loadAnswers builds the answer bank from the config and the questions
+// answered with `jb questions`.
+func loadAnswers(ctx context.Context, cfg []config.Answer, ds datastore.Datastore) (*answers.Bank, error) {
+	bank, err := answers.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := ds.GetQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range questions {
+		if q.Answered {
+			bank.Add(q.Label, q.Answer)
+		}
+	}
+
+	return bank, nil
 }
@@ -0,0 +1,48 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/indeed"
+	"github.com/k1ng440/job-bot/internal/linkedin"
+)
+
+func TestHeadless(t *testing.T) {
+	cfg := config.Config{Linkedin: config.Linkedin{Headless: true}}
+
+	if !headless(cfg, linkedin.Name) {
+		t.Fatal("expected linkedin to run headless")
+	}
+	if headless(cfg, indeed.Name) {
+		t.Fatal("expected indeed to keep its own setting and show a window")
+	}
+
+	cfg.Indeed.Headless = true
+	if !headless(cfg, indeed.Name) {
+		t.Fatal("expected indeed to run headless")
+	}
+}
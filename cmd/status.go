@@ -0,0 +1,144 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the application funnel",
+		Long: `Prints how many jobs are in each status of the application lifecycle,
+and how many ever reached it.
+
+Statuses: ` + strings.Join(datastore.Statuses, ", "),
+		Args: cobra.NoArgs,
+		RunE: showFunnel,
+	}
+	statusHistoryCmd = &cobra.Command{
+		Use:   "history <job id>",
+		Short: "Show the status history of a job",
+		Args:  cobra.ExactArgs(1),
+		RunE:  showHistory,
+	}
+	statusSetCmd = &cobra.Command{
+		Use:   "set <job id> <status>",
+		Short: "Record the status of a job, e.g. rejected, interview or offer",
+		Args:  cobra.ExactArgs(2),
+		RunE:  setStatus,
+	}
+	statusPlatform string
+)
+
+func init() {
+	statusCmd.PersistentFlags().StringVarP(&statusPlatform, "platform", "p", "linkedin", "platform of the job")
+	statusCmd.AddCommand(statusHistoryCmd)
+	statusCmd.AddCommand(statusSetCmd)
+}
+
+func showFunnel(cmd *cobra.Command, _ []string) error {
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	counts, err := ds.GetStatusCounts(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	funnel, err := ds.GetStatusFunnel(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tNOW\tREACHED")
+	for _, status := range datastore.Statuses {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", status, counts[status], funnel[status])
+	}
+
+	return w.Flush()
+}
+
+func showHistory(cmd *cobra.Command, args []string) error {
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	history, err := ds.GetJobPostingHistory(cmd.Context(), statusPlatform, args[0])
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("no %s job with id %s", statusPlatform, args[0])
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tFROM\tTO")
+	for _, change := range history {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", change.ChangedAt.Local().Format("2006-01-02 15:04:05"), change.From, change.To)
+	}
+
+	return w.Flush()
+}
+
+func setStatus(cmd *cobra.Command, args []string) error {
+	id, status := args[0], args[1]
+	if !datastore.IsStatus(status) {
+		return fmt.Errorf("unknown status %q, expected one of %s", status, strings.Join(datastore.Statuses, ", "))
+	}
+
+	ds, err := openDatastore()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create datastore")
+		return err
+	}
+	defer ds.Close()
+
+	if err := ds.SetJobPostingStatus(cmd.Context(), statusPlatform, id, status); err != nil {
+		if errors.Is(err, datastore.ErrNotFound) {
+			return fmt.Errorf("no %s job with id %s", statusPlatform, id)
+		}
+		return err
+	}
+
+	log.Info().Str("id", id).Str("status", status).Msg("Job status updated")
+	return nil
+}
@@ -0,0 +1,145 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package answers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/k1ng440/job-bot/internal/config"
+)
+
+type answer struct {
+	question *regexp.Regexp
+	value    string
+}
+
+// Bank holds the configured answers to application form questions.
+type Bank struct {
+	answers []answer
+}
+
+// New compiles the configured answers into a Bank.
+func New(cfg []config.Answer) (*Bank, error) {
+	b := &Bank{answers: make([]answer, 0, len(cfg))}
+	for _, a := range cfg {
+		re, err := regexp.Compile(a.Question)
+		if err != nil {
+			return nil, fmt.Errorf("invalid question pattern %q. %w", a.Question, err)
+		}
+
+		b.answers = append(b.answers, answer{question: re, value: a.Answer})
+	}
+
+	return b, nil
+}
+
+// With returns a copy of the bank with the configured answers matched
+// before the answers of the bank.
+func (b *Bank) With(cfg []config.Answer) (*Bank, error) {
+	w, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if b != nil {
+		w.answers = append(w.answers, b.answers...)
+	}
+
+	return w, nil
+}
+
+// Add appends an answer to a question with exactly the given label.
+func (b *Bank) Add(label, value string) {
+	b.answers = append(b.answers, answer{
+		question: regexp.MustCompile(`(?i)^\s*` + regexp.QuoteMeta(strings.TrimSpace(label)) + `\s*$`),
+		value:    value,
+	})
+}
+
+// Lookup returns the answer of the first entry whose pattern matches the label.
+// A nil bank has no answers.
+func (b *Bank) Lookup(label string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+
+	label = strings.TrimSpace(label)
+	for _, a := range b.answers {
+		if a.question.MatchString(label) {
+			return a.value, true
+		}
+	}
+
+	return "", false
+}
+
+// Choose looks up the answer for the label and picks the option it refers to.
+// Options are compared case-insensitively, preferring an exact match over
+// an option containing the answer.
+func (b *Bank) Choose(label string, options []string) (string, bool) {
+	value, ok := b.Lookup(label)
+	if !ok {
+		return "", false
+	}
+
+	return Match(value, options)
+}
+
+// Match picks the option referred to by the answer.
+func Match(value string, options []string) (string, bool) {
+	value = normalize(value)
+	if value == "" {
+		return "", false
+	}
+
+	for _, o := range options {
+		if normalize(o) == value {
+			return o, true
+		}
+	}
+
+	for _, o := range options {
+		if strings.Contains(normalize(o), value) {
+			return o, true
+		}
+	}
+
+	return "", false
+}
+
+// Checked reports whether the answer means a checkbox should be checked.
+func Checked(value string) bool {
+	switch normalize(value) {
+	case "yes", "true", "y", "1", "on", "checked":
+		return true
+	default:
+		return false
+	}
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
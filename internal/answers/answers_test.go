@@ -0,0 +1,147 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package answers_test
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+)
+
+func newBank(t *testing.T) *answers.Bank {
+	bank, err := answers.New([]config.Answer{
+		{Question: `(?i)years.*\bgo(lang)?\b`, Answer: "5"},
+		{Question: `(?i)years`, Answer: "3"},
+		{Question: `(?i)sponsorship`, Answer: "No"},
+		{Question: `(?i)authorized to work`, Answer: "yes"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create answer bank: %v", err)
+	}
+
+	return bank
+}
+
+func TestNewInvalidPattern(t *testing.T) {
+	_, err := answers.New([]config.Answer{{Question: `(`, Answer: "x"}})
+	if err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	bank := newBank(t)
+
+	// The first matching entry wins
+	value, ok := bank.Lookup("How many years of work experience do you have with Go?")
+	if !ok || value != "5" {
+		t.Fatalf("expected 5, got %q (found: %v)", value, ok)
+	}
+
+	value, ok = bank.Lookup("How many years of work experience do you have with Kubernetes?")
+	if !ok || value != "3" {
+		t.Fatalf("expected 3, got %q (found: %v)", value, ok)
+	}
+
+	if _, ok := bank.Lookup("What is your notice period?"); ok {
+		t.Fatal("expected no answer for an unknown question")
+	}
+}
+
+func TestWith(t *testing.T) {
+	bank := newBank(t)
+
+	search, err := bank.With([]config.Answer{{Question: `(?i)years`, Answer: "7"}})
+	if err != nil {
+		t.Fatalf("failed to create answer bank: %v", err)
+	}
+
+	// The added answers win over the bank's
+	value, ok := search.Lookup("How many years of work experience do you have with Go?")
+	if !ok || value != "7" {
+		t.Fatalf("expected 7, got %q (found: %v)", value, ok)
+	}
+
+	value, ok = search.Lookup("Will you require sponsorship?")
+	if !ok || value != "No" {
+		t.Fatalf("expected No, got %q (found: %v)", value, ok)
+	}
+
+	// The bank itself is left alone
+	value, ok = bank.Lookup("How many years of work experience do you have with Go?")
+	if !ok || value != "5" {
+		t.Fatalf("expected 5, got %q (found: %v)", value, ok)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	bank := newBank(t)
+	bank.Add("What is your notice period? (weeks)", "4")
+
+	value, ok := bank.Lookup("what is your notice period? (weeks)")
+	if !ok || value != "4" {
+		t.Fatalf("expected 4, got %q (found: %v)", value, ok)
+	}
+
+	if _, ok := bank.Lookup("What is your notice period?"); ok {
+		t.Fatal("expected added answers to only match the exact label")
+	}
+}
+
+func TestChoose(t *testing.T) {
+	bank := newBank(t)
+
+	option, ok := bank.Choose("Will you now or in the future require sponsorship?", []string{"Yes", "No"})
+	if !ok || option != "No" {
+		t.Fatalf("expected No, got %q (found: %v)", option, ok)
+	}
+
+	option, ok = bank.Choose("Are you legally authorized to work in Germany?", []string{"No, I am not", "Yes, I am"})
+	if !ok || option != "Yes, I am" {
+		t.Fatalf("expected %q, got %q (found: %v)", "Yes, I am", option, ok)
+	}
+
+	if _, ok := bank.Choose("Will you now or in the future require sponsorship?", []string{"Maybe"}); ok {
+		t.Fatal("expected no option to match")
+	}
+}
+
+func TestChecked(t *testing.T) {
+	for value, want := range map[string]bool{"Yes": true, "true": true, " 1 ": true, "no": false, "": false} {
+		if got := answers.Checked(value); got != want {
+			t.Fatalf("Checked(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestNilBank(t *testing.T) {
+	var bank *answers.Bank
+	if _, ok := bank.Lookup("How many years of experience do you have with Go?"); ok {
+		t.Fatal("expected a nil bank to have no answers")
+	}
+	if _, ok := bank.Choose("Are you legally authorized to work in Germany?", []string{"Yes", "No"}); ok {
+		t.Fatal("expected a nil bank to choose no option")
+	}
+}
@@ -0,0 +1,219 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package ats applies for jobs on the application forms of applicant
+// tracking systems, which job boards link to for jobs not applied to on the
+// board itself.
+package ats
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/form"
+	"github.com/rs/zerolog/log"
+)
+
+// ATS is the application form layout of an applicant tracking system.
+type ATS struct {
+	Name string
+
+	// hosts serve the job postings of the ATS
+	hosts []string
+	// formPath is added to the posting url when the form is on its own page
+	formPath string
+
+	form              string
+	group             string
+	groupLabel        string
+	resumeUpload      string
+	coverLetterUpload string
+	submit            string
+	confirmation      string
+}
+
+var (
+	// Greenhouse job boards show the application form below the posting.
+	Greenhouse = &ATS{
+		Name:              "greenhouse",
+		hosts:             []string{"boards.greenhouse.io", "job-boards.greenhouse.io"},
+		form:              `#application_form`,
+		group:             `fieldset, #application_form .field`,
+		groupLabel:        `legend, label`,
+		resumeUpload:      `#s3_upload_for_resume input[type="file"]`,
+		coverLetterUpload: `#s3_upload_for_cover_letter input[type="file"]`,
+		submit:            `#submit_app`,
+		confirmation:      `#application_confirmation`,
+	}
+
+	// Lever postings link to the application form on the apply page.
+	Lever = &ATS{
+		Name:              "lever",
+		hosts:             []string{"jobs.lever.co", "jobs.eu.lever.co"},
+		formPath:          "/apply",
+		form:              `form#application-form`,
+		group:             `fieldset, #application-form .application-question`,
+		groupLabel:        `legend, .application-label`,
+		resumeUpload:      `input#resume-upload-input[type="file"]`,
+		coverLetterUpload: `input#cover-letter-upload-input[type="file"]`,
+		submit:            `button#btn-submit`,
+		confirmation:      `.application-confirmation`,
+	}
+
+	systems = []*ATS{Greenhouse, Lever}
+)
+
+// Applicant is what is sent with an application.
+type Applicant struct {
+	Answers *answers.Bank
+	// Resume is the resume file to upload
+	Resume      string
+	CoverLetter config.CoverLetter
+}
+
+// Detect returns the ATS serving the application form at the url, or nil
+// when it is not supported.
+func Detect(applyUrl string) *ATS {
+	u, err := url.Parse(applyUrl)
+	if err != nil {
+		return nil
+	}
+
+	for _, a := range systems {
+		for _, host := range a.hosts {
+			if strings.EqualFold(u.Hostname(), host) {
+				return a
+			}
+		}
+	}
+
+	return nil
+}
+
+// formUrl returns the url of the application form of the posting.
+func (a *ATS) formUrl(applyUrl string) (string, error) {
+	u, err := url.Parse(applyUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse apply url. %w", err)
+	}
+
+	if a.formPath != "" && !strings.HasSuffix(u.Path, a.formPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + a.formPath
+	}
+
+	return u.String(), nil
+}
+
+// Apply opens the application form of the posting's apply url, fills it
+// and submits it. It returns the status the posting moves to. Unanswered
+// questions are stored with the posting so it can be retried once answered.
+func (a *ATS) Apply(ctx context.Context, ds datastore.Datastore, post *datastore.JobPosting, applicant Applicant) (string, error) {
+	log.Info().Str("title", post.Title).Str("ats", a.Name).Msg("Applying for job on company site")
+
+	u, err := a.formUrl(post.ApplyUrl)
+	if err != nil {
+		return datastore.StatusFailed, err
+	}
+
+	if err := cdp.Run(ctx,
+		cdp.Navigate(u),
+		cdp.WaitVisible(a.form, cdp.ByQuery),
+	); err != nil {
+		return datastore.StatusFailed, fmt.Errorf("failed to open %s application form. %w", a.Name, err)
+	}
+
+	for _, f := range []struct {
+		name string
+		sel  string
+		path string
+	}{
+		{"resume", a.resumeUpload, applicant.Resume},
+		{"cover letter", a.coverLetterUpload, applicant.CoverLetter.Path},
+	} {
+		if err := upload(ctx, f.sel, f.path); err != nil {
+			return datastore.StatusFailed, fmt.Errorf("failed to upload %s. %w", f.name, err)
+		}
+	}
+
+	filler := &form.Filler{
+		Bank: applicant.Answers,
+		Text: func(f *form.Field) (string, bool) {
+			return form.CoverLetterText(applicant.CoverLetter, post, f)
+		},
+		Group:      a.group,
+		GroupLabel: a.groupLabel,
+	}
+
+	unanswered, err := filler.Fill(ctx, a.form)
+	if err != nil {
+		return datastore.StatusFailed, err
+	}
+	if len(unanswered) > 0 {
+		return form.SaveQuestions(ctx, ds, post, unanswered)
+	}
+
+	if err := cdp.Run(ctx, cdp.Click(a.submit, cdp.ByQuery)); err != nil {
+		return datastore.StatusFailed, fmt.Errorf("failed to submit application. %w", err)
+	}
+
+	// Captchas and invalid answers keep the form from being sent
+	waitCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := cdp.Run(waitCtx, cdp.WaitVisible(a.confirmation, cdp.ByQuery)); err != nil {
+		log.Warn().Err(err).Str("title", post.Title).Str("ats", a.Name).Msg("Application was not confirmed")
+		return datastore.StatusNeedsHuman, nil
+	}
+
+	log.Info().Str("title", post.Title).Msg("Application submitted")
+	return datastore.StatusSubmitted, nil
+}
+
+// upload sets the file of the upload input, when the form has one.
+func upload(ctx context.Context, sel, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	ok, err := form.Exists(ctx, sel)
+	if err != nil || !ok {
+		return err
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	return cdp.Run(ctx,
+		cdp.SetUploadFiles(sel, []string{path}, cdp.ByQuery),
+		cdp.Sleep(2*time.Second),
+	)
+}
@@ -0,0 +1,135 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package ats
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/browsertest"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		url  string
+		ats  *ATS
+		form string
+	}{
+		{"https://boards.greenhouse.io/acme/jobs/123", Greenhouse, "https://boards.greenhouse.io/acme/jobs/123"},
+		{"https://job-boards.greenhouse.io/acme/jobs/123?gh_src=linkedin", Greenhouse, "https://job-boards.greenhouse.io/acme/jobs/123?gh_src=linkedin"},
+		{"https://Boards.Greenhouse.io/acme/jobs/123", Greenhouse, "https://Boards.Greenhouse.io/acme/jobs/123"},
+		{"https://jobs.lever.co/acme/0f4b", Lever, "https://jobs.lever.co/acme/0f4b/apply"},
+		{"https://jobs.lever.co/acme/0f4b/", Lever, "https://jobs.lever.co/acme/0f4b/apply"},
+		{"https://jobs.lever.co/acme/0f4b/apply", Lever, "https://jobs.lever.co/acme/0f4b/apply"},
+		{"https://jobs.eu.lever.co/acme/0f4b?lever-source=LinkedIn", Lever, "https://jobs.eu.lever.co/acme/0f4b/apply?lever-source=LinkedIn"},
+		{"https://jobs.lever.co:443/acme/0f4b", Lever, "https://jobs.lever.co:443/acme/0f4b/apply"},
+		// Look-alike hosts and company sites are not supported
+		{"https://boards.greenhouse.io.example.com/acme/jobs/123", nil, ""},
+		{"https://careers.acme.com/jobs/123?gh_jid=123", nil, ""},
+		{"https://www.linkedin.com/redir/redirect?url=https%3A%2F%2Fjobs.lever.co%2Facme", nil, ""},
+		{"jobs.lever.co/acme/0f4b", nil, ""},
+		{"", nil, ""},
+	} {
+		a := Detect(tc.url)
+		if a != tc.ats {
+			t.Fatalf("expected %q to be detected as %v, got %v", tc.url, tc.ats, a)
+		}
+		if a == nil {
+			continue
+		}
+
+		form, err := a.formUrl(tc.url)
+		if err != nil || form != tc.form {
+			t.Fatalf("expected form url %q for %q, got %q: %v", tc.form, tc.url, form, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := browsertest.NewBrowser(t)
+
+	mux := http.NewServeMux()
+	mux.Handle("/acme/jobs/1", browsertest.Page("greenhouse"))
+	mux.Handle("/acme/0f4b/apply", browsertest.Page("lever"))
+	srv := browsertest.NewServer(t, mux)
+
+	bank := []config.Answer{
+		{Question: `(?i)^first name`, Answer: "Jane"},
+		{Question: `(?i)^last name`, Answer: "Doe"},
+		{Question: `(?i)^full name`, Answer: "Jane Doe"},
+		{Question: `(?i)^email`, Answer: "jane@example.com"},
+		{Question: `(?i)languages do you speak`, Answer: "English, German"},
+		{Question: `(?i)authorized to work`, Answer: "Yes"},
+	}
+	// The forms only confirm applications with every required question answered
+	noAuthorization := bank[:len(bank)-1]
+
+	for _, tc := range []struct {
+		ats       *ATS
+		applyUrl  string
+		bank      []config.Answer
+		status    string
+		questions int
+	}{
+		{Greenhouse, srv.URL + "/acme/jobs/1", bank, datastore.StatusSubmitted, 0},
+		{Greenhouse, srv.URL + "/acme/jobs/1", noAuthorization, datastore.StatusNeedsAnswer, 1},
+		{Lever, srv.URL + "/acme/0f4b", bank, datastore.StatusSubmitted, 0},
+		{Lever, srv.URL + "/acme/0f4b", noAuthorization, datastore.StatusNeedsAnswer, 1},
+	} {
+		b, err := answers.New(tc.bank)
+		if err != nil {
+			t.Fatalf("failed to create answer bank: %v", err)
+		}
+
+		ds := datastore.NewMemoryDatastore()
+		post := &datastore.JobPosting{Platform: "linkedin", ID: tc.ats.Name, Title: "Golang Engineer", Company: "Acme", ApplyUrl: tc.applyUrl}
+		if err := ds.InsertJobPosting(ctx, post); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+
+		status, err := tc.ats.Apply(ctx, ds, post, Applicant{
+			Answers:     b,
+			Resume:      filepath.Join("testdata", "resume.pdf"),
+			CoverLetter: config.CoverLetter{Text: "I would love to write Go at {company}."},
+		})
+		if err != nil || status != tc.status {
+			t.Fatalf("expected the %s application to end %s, got %s: %v", tc.ats.Name, tc.status, status, err)
+		}
+
+		questions, err := ds.GetQuestions(ctx)
+		if err != nil {
+			t.Fatalf("failed to get questions: %v", err)
+		}
+		if len(questions) != tc.questions {
+			t.Fatalf("expected %d queued %s questions, got %+v", tc.questions, tc.ats.Name, questions)
+		}
+		if tc.questions > 0 && len(questions[0].Options) != 2 {
+			t.Fatalf("expected the work authorization options to be queued, got %+v", questions[0])
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package browsertest runs the browser tests of the job board and ATS
+// packages against pages saved in their testdata directory.
+package browsertest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	cdp "github.com/chromedp/chromedp"
+)
+
+// chromeNames are the executables chromedp looks for.
+var chromeNames = []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "headless-shell", "chrome"}
+
+// NewBrowser starts a headless browser, skipping the test when chrome is not installed.
+func NewBrowser(t *testing.T) context.Context {
+	t.Helper()
+
+	found := false
+	for _, name := range chromeNames {
+		if _, err := exec.LookPath(name); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Skip("chrome is not installed")
+	}
+
+	allocCtx, cancel := cdp.NewExecAllocator(context.Background(),
+		append(cdp.DefaultExecAllocatorOptions[:], cdp.NoSandbox)...)
+	t.Cleanup(cancel)
+
+	ctx, cancel := cdp.NewContext(allocCtx)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// Page serves the saved page testdata/<name>.html.
+func Page(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join("testdata", name+".html"))
+	}
+}
+
+// NewServer serves the saved pages routed by mux until the test ends.
+func NewServer(t *testing.T, mux *http.ServeMux) *httptest.Server {
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
@@ -25,23 +25,210 @@ package datastore
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when inserting a record that is already stored.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrInvalidTransition is returned when a job posting cannot move from
+	// its current status to the requested one.
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrTaken is returned when moving a job posting to applying while it is
+	// already being applied for, possibly by a bot on another machine.
+	ErrTaken = errors.New("job posting is already being applied for")
+)
+
+// The statuses of the application lifecycle of a job posting.
+const (
+	// StatusDiscovered marks a job posting found by a search. Every job
+	// posting starts in it and moves on once the filters have run.
+	StatusDiscovered = "discovered"
+	// StatusFiltered marks a job posting rejected by the filters.
+	// The rejecting rule is stored in FilterReason.
+	StatusFiltered = "filtered"
+	// StatusQueued marks a job posting waiting to be applied to.
+	StatusQueued = "queued"
+	// StatusApplying marks a job posting whose application is in progress.
+	// It is left behind when a run is interrupted while applying.
+	StatusApplying = "applying"
+	// StatusSubmitted marks a job posting whose application was sent.
+	StatusSubmitted = "submitted"
+	// StatusFailed marks a job posting whose application could not be completed.
+	StatusFailed = "failed"
+	// StatusNeedsHuman marks a job posting whose application form has to be
+	// completed by hand.
+	StatusNeedsHuman = "needs_human"
+	// StatusNeedsAnswer marks a job posting whose application form asked
+	// questions the answer bank could not answer.
+	StatusNeedsAnswer = "needs_answer"
+	// StatusSkipped marks a job posting skipped because the application
+	// limit of its company or search was reached for the day.
+	StatusSkipped = "skipped"
+	// StatusRejected, StatusInterview and StatusOffer record the response
+	// of the company to a submitted application.
+	StatusRejected  = "rejected"
+	StatusInterview = "interview"
+	StatusOffer     = "offer"
+)
+
+// Statuses lists the application lifecycle in funnel order.
+var Statuses = []string{
+	StatusDiscovered,
+	StatusFiltered,
+	StatusQueued,
+	StatusApplying,
+	StatusNeedsAnswer,
+	StatusNeedsHuman,
+	StatusSkipped,
+	StatusFailed,
+	StatusSubmitted,
+	StatusInterview,
+	StatusOffer,
+	StatusRejected,
+}
+
+// transitions lists the statuses each status can move to.
+var transitions = map[string][]string{
+	StatusDiscovered:  {StatusFiltered, StatusQueued},
+	StatusFiltered:    {StatusQueued},
+	StatusQueued:      {StatusApplying, StatusFiltered, StatusSkipped, StatusFailed, StatusNeedsAnswer, StatusNeedsHuman, StatusSubmitted},
+	StatusApplying:    {StatusSubmitted, StatusFailed, StatusNeedsHuman, StatusNeedsAnswer, StatusSkipped, StatusQueued},
+	StatusNeedsAnswer: {StatusApplying, StatusQueued, StatusSkipped, StatusFailed, StatusNeedsHuman, StatusSubmitted},
+	StatusNeedsHuman:  {StatusApplying, StatusQueued, StatusFailed, StatusSubmitted},
+	StatusSkipped:     {StatusApplying, StatusQueued},
+	StatusFailed:      {StatusApplying, StatusQueued},
+	StatusSubmitted:   {StatusInterview, StatusOffer, StatusRejected},
+	StatusInterview:   {StatusOffer, StatusRejected},
+	StatusOffer:       {StatusRejected},
+}
+
+// CanTransition reports whether a job posting can move between the statuses.
+func CanTransition(from, to string) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStatus reports whether s is a status of the application lifecycle.
+func IsStatus(s string) bool {
+	for _, status := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 type JobPosting struct {
 	Platform string
 	ID       string
 	Url      string
 	Title    string
 	Company  string
-	Applied  bool
+	// Status is where the job posting is in the application lifecycle
+	Status string
+	// PostedAt is when the job was posted, or the zero time when unknown
+	PostedAt time.Time
+	// FilterReason is the filter rule that rejected the posting, if any
+	FilterReason string
+	Location     string
+	Seniority    string
+	// Score is how well the posting fits, made up of ScoreDetails
+	Score        float64
+	ScoreDetails []ScoreContribution
+	// WorkplaceType is remote, hybrid or on-site
+	WorkplaceType string
+	// EmploymentType is full-time, part-time, contract and so on
+	EmploymentType string
+	// ApplicantCount is the number of applicants shown, or 0 when unknown
+	ApplicantCount int
+	// Salary is the salary range as shown on the posting, if any
+	Salary string
+	// HiringTeam is the name of the recruiter or hiring manager, if shown
+	HiringTeam string
+	// Description is the full text of the posting
+	Description string
+	// Search is the name of the configured search that found the posting
+	Search string
+	// ApplyUrl is the application form on the company site, for postings
+	// not applied to on the platform itself
+	ApplyUrl string
+}
+
+// Applied reports whether an application was sent for the job posting.
+func (p *JobPosting) Applied() bool {
+	switch p.Status {
+	case StatusSubmitted, StatusInterview, StatusOffer, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// StatusChange is an entry of the status history of a job posting.
+type StatusChange struct {
+	From      string
+	To        string
+	ChangedAt time.Time
+}
+
+// ScoreContribution is the weight a scoring rule added to a posting's score.
+type ScoreContribution struct {
+	Rule   string  `json:"rule"`
+	Weight float64 `json:"weight"`
+}
+
+// Question is an application form question that had no configured answer.
+type Question struct {
+	ID       int64
+	Label    string
+	Kind     string
+	Options  []string
+	Answer   string
+	Answered bool
+}
+
+// Checkpoint is how far a search url has been crawled, so an interrupted
+// crawl can resume where it left off.
+type Checkpoint struct {
+	Platform string
+	Url      string
+	// Start is the offset of the search page being crawled
+	Start int
+	// JobIDs are the jobs already seen at or just before Start
+	JobIDs    []string
+	UpdatedAt time.Time
 }
 
 type Datastore interface {
 	IncAppliedTodayCount(ctx context.Context, platform string) error
-	GetAppliedTodayCount(ctx context.Context) (int, error)
+	GetAppliedTodayCount(ctx context.Context, platforms ...string) (int, error)
 	GetAppliedCountByCompany(ctx context.Context, name string) (int, error)
 	IncAppliedCountByCompany(ctx context.Context, name string) error
+	GetAppliedTodayCountBySearch(ctx context.Context, platform, search string) (int, error)
 	InsertJobPosting(ctx context.Context, jobPosting *JobPosting) error
-	GetUnappliedJobPosting(ctx context.Context) (*JobPosting, error)
+	GetUnappliedJobPosting(ctx context.Context, platforms ...string) (*JobPosting, error)
+	GetUnappliedJobPostings(ctx context.Context) ([]*JobPosting, error)
+	RequeueJobPostings(ctx context.Context, statuses ...string) (int, error)
+	RequeueStaleJobPostings(ctx context.Context, status string, before time.Time) (int, error)
+	GetJobPostings(ctx context.Context) ([]*JobPosting, error)
+	SetJobPostingStatus(ctx context.Context, platform, id, status string) error
+	GetJobPostingHistory(ctx context.Context, platform, id string) ([]*StatusChange, error)
+	GetStatusCounts(ctx context.Context) (map[string]int, error)
+	GetStatusFunnel(ctx context.Context) (map[string]int, error)
+	GetRetryableJobPostings(ctx context.Context, platforms ...string) ([]*JobPosting, error)
+	InsertQuestion(ctx context.Context, platform, jobID string, question *Question) error
+	GetQuestions(ctx context.Context) ([]*Question, error)
+	AnswerQuestion(ctx context.Context, id int64, answer string) error
+	GetCheckpoint(ctx context.Context, platform, url string) (*Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error
+	DeleteCheckpoint(ctx context.Context, platform, url string) error
+	DeleteCheckpoints(ctx context.Context) error
 	Close() error
 }
@@ -0,0 +1,825 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	_ "github.com/mattn/go-sqlite3" // Import the SQLite3 driver
+)
+
+// testDBFile is the temporary SQLite database file used for testing.
+const testDBFile = "test.db"
+
+// conformance lists the tests every datastore implementation has to pass.
+var conformance = []struct {
+	name string
+	test func(t *testing.T, ds datastore.Datastore)
+}{
+	{"InsertJobPosting", testInsertJobPosting},
+	{"InsertDuplicateJobPosting", testInsertDuplicateJobPosting},
+	{"IncAppliedTodayCount", testIncAppliedTodayCount},
+	{"IncAppliedCountByCompany", testIncAppliedCountByCompany},
+	{"GetUnappliedJobPosting", testGetUnappliedJobPosting},
+	{"GetAppliedCountByCompany", testGetAppliedCountByCompany},
+	{"GetAppliedTodayCount", testGetAppliedTodayCount},
+	{"GetRetryableJobPostings", testGetRetryableJobPostings},
+	{"GetAppliedTodayCountAcrossPlatforms", testGetAppliedTodayCountAcrossPlatforms},
+	{"GetAppliedTodayCountBySearch", testGetAppliedTodayCountBySearch},
+	{"GetUnappliedJobPostingSkipsFiltered", testGetUnappliedJobPostingSkipsFiltered},
+	{"GetUnappliedJobPostingByScore", testGetUnappliedJobPostingByScore},
+	{"GetUnappliedJobPostingByPlatform", testGetUnappliedJobPostingByPlatform},
+	{"GetUnappliedJobPostingsByRecency", testGetUnappliedJobPostingsByRecency},
+	{"Checkpoints", testCheckpoints},
+	{"RequeueJobPostings", testRequeueJobPostings},
+	{"RequeueStaleJobPostings", testRequeueStaleJobPostings},
+	{"JobPostingStatusHistory", testJobPostingStatusHistory},
+}
+
+// runConformance runs the conformance tests against the datastores created by setup.
+func runConformance(t *testing.T, setup func(t *testing.T) (datastore.Datastore, func())) {
+	for _, c := range conformance {
+		t.Run(c.name, func(t *testing.T) {
+			ds, cleanup := setup(t)
+			defer cleanup()
+
+			c.test(t, ds)
+		})
+	}
+}
+
+func TestSqlite(t *testing.T) {
+	runConformance(t, setupDB)
+
+	t.Run("ClaimRace", func(t *testing.T) {
+		a, cleanup := setupDB(t)
+		defer cleanup()
+
+		b, err := datastore.NewSqliteDatastore(testDBFile)
+		if err != nil {
+			t.Fatalf("failed to open test database: %v", err)
+		}
+		defer b.Close()
+
+		testClaimRace(t, a, b)
+	})
+}
+
+func TestMemory(t *testing.T) {
+	runConformance(t, func(t *testing.T) (datastore.Datastore, func()) {
+		ds := datastore.NewMemoryDatastore()
+		return ds, func() { ds.Close() }
+	})
+
+	t.Run("ClaimRace", func(t *testing.T) {
+		ds := datastore.NewMemoryDatastore()
+		defer ds.Close()
+
+		testClaimRace(t, ds, ds)
+	})
+}
+
+// setupDB initializes a test database and returns a datastore.Datastore for testing.
+func setupDB(t *testing.T) (datastore.Datastore, func()) {
+	// Open the database
+	ds, err := datastore.NewSqliteDatastore(testDBFile)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	cleanup := func() {
+		ds.Close()
+		os.Remove(testDBFile)
+	}
+
+	return ds, cleanup
+}
+
+func testInsertJobPosting(t *testing.T, ds datastore.Datastore) {
+	// Create a test JobPosting
+	jobPosting := &datastore.JobPosting{
+		Platform: "TestPlatform",
+		ID:       "123",
+		Url:      "https://test.com/job/testid",
+		Title:    "Test Job",
+		Company:  "Test Company",
+		PostedAt: time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC),
+
+		WorkplaceType:  "remote",
+		EmploymentType: "full-time",
+		ApplicantCount: 42,
+		Salary:         "$120K/yr - $150K/yr",
+		HiringTeam:     "Jane Doe",
+		Description:    "We are looking for a Go developer",
+		ApplyUrl:       "https://boards.greenhouse.io/test/jobs/123",
+	}
+
+	// Insert the test JobPosting
+	err := ds.InsertJobPosting(context.Background(), jobPosting)
+	if err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+
+	// Retrieve the inserted JobPosting from the database
+	retrievedJobPosting, err := ds.GetUnappliedJobPosting(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve job posting: %v", err)
+	}
+
+	// Check that the retrieved JobPosting matches the inserted one
+	if retrievedJobPosting == nil {
+		t.Fatal("no job posting found in the database")
+	}
+	if retrievedJobPosting.Platform != jobPosting.Platform ||
+		retrievedJobPosting.ID != jobPosting.ID ||
+		retrievedJobPosting.Url != jobPosting.Url ||
+		retrievedJobPosting.Title != jobPosting.Title ||
+		retrievedJobPosting.Company != jobPosting.Company ||
+		retrievedJobPosting.Status != datastore.StatusQueued ||
+		retrievedJobPosting.Applied() ||
+		!retrievedJobPosting.PostedAt.Equal(jobPosting.PostedAt) {
+		t.Fatal("retrieved job posting does not match the inserted one")
+	}
+	if retrievedJobPosting.WorkplaceType != jobPosting.WorkplaceType ||
+		retrievedJobPosting.EmploymentType != jobPosting.EmploymentType ||
+		retrievedJobPosting.ApplicantCount != jobPosting.ApplicantCount ||
+		retrievedJobPosting.Salary != jobPosting.Salary ||
+		retrievedJobPosting.HiringTeam != jobPosting.HiringTeam ||
+		retrievedJobPosting.Description != jobPosting.Description ||
+		retrievedJobPosting.ApplyUrl != jobPosting.ApplyUrl {
+		t.Fatalf("job details were not stored: %+v", retrievedJobPosting)
+	}
+}
+
+func testInsertDuplicateJobPosting(t *testing.T, ds datastore.Datastore) {
+	jobPosting := &datastore.JobPosting{Platform: "TestPlatform", ID: "123", Title: "Test Job"}
+	if err := ds.InsertJobPosting(context.Background(), jobPosting); err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+
+	// The same job posting found by another search is not stored twice
+	err := ds.InsertJobPosting(context.Background(), jobPosting)
+	if !errors.Is(err, datastore.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	posts, err := ds.GetJobPostings(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get job postings: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected 1 job posting, got %d", len(posts))
+	}
+}
+
+func testIncAppliedTodayCount(t *testing.T, ds datastore.Datastore) {
+	// Increment applied count for a platform
+	platform := "TestPlatform"
+	err := ds.IncAppliedTodayCount(context.Background(), platform)
+	if err != nil {
+		t.Fatalf("failed to increment applied count: %v", err)
+	}
+
+	// Retrieve the applied count for the platform
+	count, err := ds.GetAppliedTodayCount(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count: %v", err)
+	}
+
+	// Check that the retrieved count is 1
+	if count != 1 {
+		t.Fatalf("expected applied count to be 1, got %d", count)
+	}
+}
+
+func testIncAppliedCountByCompany(t *testing.T, ds datastore.Datastore) {
+	// Increment applied count for a company
+	company := "TestCompany"
+	err := ds.IncAppliedCountByCompany(context.Background(), company)
+	if err != nil {
+		t.Fatalf("failed to increment applied count by company: %v", err)
+	}
+
+	// Retrieve the applied count for the company
+	count, err := ds.GetAppliedCountByCompany(context.Background(), company)
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count by company: %v", err)
+	}
+
+	// Check that the retrieved count is 1
+	if count != 1 {
+		t.Fatalf("expected applied count to be 1, got %d", count)
+	}
+}
+
+func testGetUnappliedJobPosting(t *testing.T, ds datastore.Datastore) {
+	// Insert a test JobPosting
+	jobPosting := &datastore.JobPosting{
+		Platform: "TestPlatform",
+		ID:       "123",
+		Url:      "https://example.com",
+		Title:    "Test Job",
+		Company:  "Test Company",
+	}
+	err := ds.InsertJobPosting(context.Background(), jobPosting)
+	if err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+
+	// Retrieve an unapplied job posting from the database
+	retrievedJobPosting, err := ds.GetUnappliedJobPosting(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve unapplied job posting: %v", err)
+	}
+
+	// Check that the retrieved JobPosting matches the inserted one
+	if retrievedJobPosting == nil {
+		t.Fatal("no unapplied job posting found in the database")
+	}
+	if retrievedJobPosting.ID != jobPosting.ID {
+		t.Fatal("retrieved job posting does not match the inserted one")
+	}
+}
+
+func testGetAppliedCountByCompany(t *testing.T, ds datastore.Datastore) {
+	// Increment applied count for a company
+	company := "TestCompany"
+	err := ds.IncAppliedCountByCompany(context.Background(), company)
+	if err != nil {
+		t.Fatalf("failed to increment applied count by company: %v", err)
+	}
+
+	// Retrieve the applied count for the company
+	count, err := ds.GetAppliedCountByCompany(context.Background(), company)
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count by company: %v", err)
+	}
+
+	// Check that the retrieved count is 1
+	if count != 1 {
+		t.Fatalf("expected applied count to be 1, got %d", count)
+	}
+}
+
+func testGetAppliedTodayCount(t *testing.T, ds datastore.Datastore) {
+	// Increment applied count for a platform
+	platform := "TestPlatform"
+	err := ds.IncAppliedTodayCount(context.Background(), platform)
+	if err != nil {
+		t.Fatalf("failed to increment applied count: %v", err)
+	}
+
+	// Retrieve the applied count for today
+	count, err := ds.GetAppliedTodayCount(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count for today: %v", err)
+	}
+
+	// Check that the retrieved count is 1
+	if count != 1 {
+		t.Fatalf("expected applied count to be 1, got %d", count)
+	}
+}
+
+func testGetRetryableJobPostings(t *testing.T, ds datastore.Datastore) {
+	// Insert a job posting waiting for an answer
+	jobPosting := &datastore.JobPosting{
+		Platform: "TestPlatform",
+		ID:       "123",
+		Url:      "https://example.com",
+		Title:    "Test Job",
+		Company:  "Test Company",
+	}
+	err := ds.InsertJobPosting(context.Background(), jobPosting)
+	if err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+
+	err = ds.SetJobPostingStatus(context.Background(), jobPosting.Platform, jobPosting.ID, datastore.StatusNeedsAnswer)
+	if err != nil {
+		t.Fatalf("failed to set job posting status: %v", err)
+	}
+
+	question := &datastore.Question{Label: "Notice period?", Kind: "select", Options: []string{"1 month", "3 months"}}
+	err = ds.InsertQuestion(context.Background(), jobPosting.Platform, jobPosting.ID, question)
+	if err != nil {
+		t.Fatalf("failed to insert question: %v", err)
+	}
+
+	// The job posting is not retryable while the question is unanswered
+	retryable, err := ds.GetRetryableJobPostings(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve retryable job postings: %v", err)
+	}
+	if len(retryable) != 0 {
+		t.Fatalf("expected no retryable job postings, got %d", len(retryable))
+	}
+
+	err = ds.AnswerQuestion(context.Background(), question.ID, "1 month")
+	if err != nil {
+		t.Fatalf("failed to answer question: %v", err)
+	}
+
+	retryable, err = ds.GetRetryableJobPostings(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve retryable job postings: %v", err)
+	}
+	if len(retryable) != 1 || retryable[0].ID != jobPosting.ID {
+		t.Fatal("expected the job posting to be retryable")
+	}
+
+	// Check that the answer was stored with the question
+	questions, err := ds.GetQuestions(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve questions: %v", err)
+	}
+	if len(questions) != 1 || questions[0].Answer != "1 month" || len(questions[0].Options) != 2 {
+		t.Fatal("retrieved question does not match the answered one")
+	}
+}
+
+func testGetAppliedTodayCountAcrossPlatforms(t *testing.T, ds datastore.Datastore) {
+	// Nothing applied yet
+	count, err := ds.GetAppliedTodayCount(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count for today: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected applied count to be 0, got %d", count)
+	}
+
+	// Increment applied count for two platforms
+	for _, platform := range []string{"TestPlatform", "OtherPlatform", "OtherPlatform"} {
+		err = ds.IncAppliedTodayCount(context.Background(), platform)
+		if err != nil {
+			t.Fatalf("failed to increment applied count: %v", err)
+		}
+	}
+
+	count, err = ds.GetAppliedTodayCount(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count for today: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected applied count to be 3, got %d", count)
+	}
+
+	// The daily limit of a platform only counts its own applications
+	for p, expected := range map[string]int{"TestPlatform": 1, "OtherPlatform": 2, "NoPlatform": 0} {
+		count, err = ds.GetAppliedTodayCount(context.Background(), p)
+		if err != nil {
+			t.Fatalf("failed to retrieve applied count for today: %v", err)
+		}
+		if count != expected {
+			t.Fatalf("expected applied count of %s to be %d, got %d", p, expected, count)
+		}
+	}
+
+	count, err = ds.GetAppliedTodayCount(context.Background(), "TestPlatform", "OtherPlatform")
+	if err != nil {
+		t.Fatalf("failed to retrieve applied count for today: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected applied count of both platforms to be 3, got %d", count)
+	}
+}
+
+func testGetAppliedTodayCountBySearch(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+
+	// Two job postings found by the same search, one by another and one by
+	// a search of the same name on another platform
+	for _, post := range []*datastore.JobPosting{
+		{Platform: "linkedin", ID: "1", Search: "Go Berlin"},
+		{Platform: "linkedin", ID: "2", Search: "Go Berlin"},
+		{Platform: "linkedin", ID: "3", Search: "SRE remote"},
+		{Platform: "indeed", ID: "1", Search: "Go Berlin"},
+	} {
+		if err := ds.InsertJobPosting(ctx, post); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	for _, key := range [][2]string{{"linkedin", "1"}, {"linkedin", "3"}, {"indeed", "1"}} {
+		for _, status := range []string{datastore.StatusApplying, datastore.StatusSubmitted} {
+			if err := ds.SetJobPostingStatus(ctx, key[0], key[1], status); err != nil {
+				t.Fatalf("failed to set job posting status: %v", err)
+			}
+		}
+	}
+
+	count, err := ds.GetAppliedTodayCountBySearch(ctx, "linkedin", "Go Berlin")
+	if err != nil {
+		t.Fatalf("failed to get applied count by search: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected applied count to be 1, got %d", count)
+	}
+
+	posts, err := ds.GetJobPostings(ctx)
+	if err != nil {
+		t.Fatalf("failed to get job postings: %v", err)
+	}
+	for _, post := range posts {
+		if post.Search == "" {
+			t.Fatalf("search was not stored: %+v", post)
+		}
+	}
+}
+
+func testGetUnappliedJobPostingSkipsFiltered(t *testing.T, ds datastore.Datastore) {
+	// Insert a job posting rejected by the filters
+	jobPosting := &datastore.JobPosting{
+		Platform:     "TestPlatform",
+		ID:           "123",
+		Url:          "https://example.com",
+		Title:        "Senior Test Job",
+		Company:      "Test Company",
+		Status:       datastore.StatusFiltered,
+		FilterReason: "title: (?i)senior",
+	}
+	err := ds.InsertJobPosting(context.Background(), jobPosting)
+	if err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+
+	// Filtered job postings are not waiting to be applied to
+	retrievedJobPosting, err := ds.GetUnappliedJobPosting(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve unapplied job posting: %v", err)
+	}
+	if retrievedJobPosting != nil {
+		t.Fatal("expected no unapplied job posting")
+	}
+}
+
+func testGetUnappliedJobPostingByScore(t *testing.T, ds datastore.Datastore) {
+	// Insert two job postings with different scores
+	for _, jobPosting := range []*datastore.JobPosting{
+		{Platform: "TestPlatform", ID: "1", Title: "Poor Fit", Score: 1},
+		{
+			Platform:     "TestPlatform",
+			ID:           "2",
+			Title:        "Good Fit",
+			Score:        8,
+			ScoreDetails: []datastore.ScoreContribution{{Rule: "title: (?i)go", Weight: 8}},
+		},
+	} {
+		err := ds.InsertJobPosting(context.Background(), jobPosting)
+		if err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	// The best scoring job posting comes first
+	retrievedJobPosting, err := ds.GetUnappliedJobPosting(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve unapplied job posting: %v", err)
+	}
+	if retrievedJobPosting == nil || retrievedJobPosting.ID != "2" {
+		t.Fatal("expected the best scoring job posting")
+	}
+	if len(retrievedJobPosting.ScoreDetails) != 1 || retrievedJobPosting.ScoreDetails[0].Weight != 8 {
+		t.Fatal("retrieved score details do not match the inserted ones")
+	}
+}
+
+func testGetUnappliedJobPostingByPlatform(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+	for _, jobPosting := range []*datastore.JobPosting{
+		{Platform: "linkedin", ID: "1", Score: 8},
+		{Platform: "indeed", ID: "2", Score: 1},
+	} {
+		if err := ds.InsertJobPosting(ctx, jobPosting); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	post, err := ds.GetUnappliedJobPosting(ctx, "indeed")
+	if err != nil {
+		t.Fatalf("failed to retrieve unapplied job posting: %v", err)
+	}
+	if post == nil || post.Platform != "indeed" {
+		t.Fatalf("expected the indeed job posting, got %+v", post)
+	}
+
+	post, err = ds.GetUnappliedJobPosting(ctx, "greenhouse")
+	if err != nil {
+		t.Fatalf("failed to retrieve unapplied job posting: %v", err)
+	}
+	if post != nil {
+		t.Fatalf("expected no job posting, got %+v", post)
+	}
+
+	// Every platform when none is given
+	post, err = ds.GetUnappliedJobPosting(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve unapplied job posting: %v", err)
+	}
+	if post == nil || post.Platform != "linkedin" {
+		t.Fatalf("expected the best scoring job posting, got %+v", post)
+	}
+}
+
+func testGetUnappliedJobPostingsByRecency(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+	postedAt := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	// Job postings with the same score, one without a posting time
+	for _, jobPosting := range []*datastore.JobPosting{
+		{Platform: "TestPlatform", ID: "unknown"},
+		{Platform: "TestPlatform", ID: "old", PostedAt: postedAt},
+		{Platform: "TestPlatform", ID: "new", PostedAt: postedAt.Add(24 * time.Hour)},
+	} {
+		if err := ds.InsertJobPosting(ctx, jobPosting); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	queue, err := ds.GetUnappliedJobPostings(ctx)
+	if err != nil {
+		t.Fatalf("failed to get unapplied job postings: %v", err)
+	}
+
+	want := []string{"new", "old", "unknown"}
+	if len(queue) != len(want) {
+		t.Fatalf("expected %d queued job postings, got %d", len(want), len(queue))
+	}
+	for i, post := range queue {
+		if post.ID != want[i] {
+			t.Fatalf("expected job posting %s at position %d, got %s", want[i], i, post.ID)
+		}
+	}
+}
+
+func testCheckpoints(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+	url := "https://www.linkedin.com/jobs/search/?keywords=golang"
+
+	if _, err := ds.GetCheckpoint(ctx, "linkedin", url); err != datastore.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	for _, start := range []int{25, 50} {
+		if err := ds.SaveCheckpoint(ctx, &datastore.Checkpoint{
+			Platform: "linkedin",
+			Url:      url,
+			Start:    start,
+			JobIDs:   []string{"1", "2"},
+		}); err != nil {
+			t.Fatalf("failed to save checkpoint: %v", err)
+		}
+	}
+
+	checkpoint, err := ds.GetCheckpoint(ctx, "linkedin", url)
+	if err != nil {
+		t.Fatalf("failed to get checkpoint: %v", err)
+	}
+	if checkpoint.Start != 50 || len(checkpoint.JobIDs) != 2 || checkpoint.JobIDs[1] != "2" {
+		t.Fatalf("unexpected checkpoint: %+v", checkpoint)
+	}
+	if time.Since(checkpoint.UpdatedAt) > time.Minute {
+		t.Fatalf("expected a recent update time, got %v", checkpoint.UpdatedAt)
+	}
+
+	if err := ds.DeleteCheckpoints(ctx); err != nil {
+		t.Fatalf("failed to delete checkpoints: %v", err)
+	}
+	if _, err := ds.GetCheckpoint(ctx, "linkedin", url); err != datastore.ErrNotFound {
+		t.Fatalf("expected ErrNotFound after reset, got %v", err)
+	}
+}
+
+func testRequeueJobPostings(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+	for _, id := range []string{"1", "2", "3"} {
+		if err := ds.InsertJobPosting(ctx, &datastore.JobPosting{Platform: "linkedin", ID: id}); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	for id, status := range map[string]string{
+		"1": datastore.StatusSubmitted,
+		"2": datastore.StatusFailed,
+		"3": datastore.StatusSkipped,
+	} {
+		if err := ds.SetJobPostingStatus(ctx, "linkedin", id, datastore.StatusApplying); err != nil {
+			t.Fatalf("failed to set job posting status: %v", err)
+		}
+		if err := ds.SetJobPostingStatus(ctx, "linkedin", id, status); err != nil {
+			t.Fatalf("failed to set job posting status: %v", err)
+		}
+	}
+
+	queue, err := ds.GetUnappliedJobPostings(ctx)
+	if err != nil {
+		t.Fatalf("failed to get unapplied job postings: %v", err)
+	}
+	if len(queue) != 0 {
+		t.Fatalf("expected an empty queue, got %d job postings", len(queue))
+	}
+
+	if _, err := ds.RequeueJobPostings(ctx, datastore.StatusSubmitted); !errors.Is(err, datastore.ErrInvalidTransition) {
+		t.Fatalf("expected submitted job postings to not be requeued, got %v", err)
+	}
+
+	n, err := ds.RequeueJobPostings(ctx, datastore.StatusFailed)
+	if err != nil {
+		t.Fatalf("failed to requeue job postings: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 requeued job posting, got %d", n)
+	}
+
+	post, err := ds.GetUnappliedJobPosting(ctx)
+	if err != nil {
+		t.Fatalf("failed to get unapplied job posting: %v", err)
+	}
+	if post == nil || post.ID != "2" {
+		t.Fatalf("expected the failed job posting to be requeued, got %+v", post)
+	}
+}
+
+func testRequeueStaleJobPostings(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+	for _, id := range []string{"1", "2"} {
+		if err := ds.InsertJobPosting(ctx, &datastore.JobPosting{Platform: "linkedin", ID: id}); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	if err := ds.SetJobPostingStatus(ctx, "linkedin", "1", datastore.StatusApplying); err != nil {
+		t.Fatalf("failed to claim job posting: %v", err)
+	}
+	if err := ds.SetJobPostingStatus(ctx, "linkedin", "1", datastore.StatusApplying); !errors.Is(err, datastore.ErrTaken) {
+		t.Fatalf("expected a claimed job posting to be taken, got %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	before := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := ds.SetJobPostingStatus(ctx, "linkedin", "2", datastore.StatusApplying); err != nil {
+		t.Fatalf("failed to claim job posting: %v", err)
+	}
+
+	// Only the job claimed before the cutoff was left behind
+	n, err := ds.RequeueStaleJobPostings(ctx, datastore.StatusApplying, before)
+	if err != nil {
+		t.Fatalf("failed to requeue stale job postings: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 requeued job posting, got %d", n)
+	}
+
+	post, err := ds.GetUnappliedJobPosting(ctx)
+	if err != nil {
+		t.Fatalf("failed to get unapplied job posting: %v", err)
+	}
+	if post == nil || post.ID != "1" {
+		t.Fatalf("expected the stale job posting to be requeued, got %+v", post)
+	}
+}
+
+// testClaimRace has two datastores sharing one database, like bots on
+// different machines, claim the same job postings at the same time.
+func testClaimRace(t *testing.T, a, b datastore.Datastore) {
+	ctx := context.Background()
+
+	const jobs = 20
+	for i := 0; i < jobs; i++ {
+		if err := a.InsertJobPosting(ctx, &datastore.JobPosting{Platform: "linkedin", ID: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		claims = map[string]int{}
+		errs   []error
+	)
+	for _, ds := range []datastore.Datastore{a, b} {
+		wg.Add(1)
+		go func(ds datastore.Datastore) {
+			defer wg.Done()
+			for i := 0; i < jobs; i++ {
+				id := strconv.Itoa(i)
+				err := ds.SetJobPostingStatus(ctx, "linkedin", id, datastore.StatusApplying)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					claims[id]++
+				case !errors.Is(err, datastore.ErrTaken):
+					errs = append(errs, err)
+				}
+				mu.Unlock()
+			}
+		}(ds)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		t.Fatalf("failed to claim job postings: %v", errs)
+	}
+	for i := 0; i < jobs; i++ {
+		if n := claims[strconv.Itoa(i)]; n != 1 {
+			t.Fatalf("expected job posting %d to be claimed once, got %d", i, n)
+		}
+	}
+}
+
+func testJobPostingStatusHistory(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+	if err := ds.InsertJobPosting(ctx, &datastore.JobPosting{Platform: "linkedin", ID: "1"}); err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+	if err := ds.InsertJobPosting(ctx, &datastore.JobPosting{
+		Platform: "linkedin",
+		ID:       "2",
+		Status:   datastore.StatusFiltered,
+	}); err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+
+	for _, status := range []string{datastore.StatusApplying, datastore.StatusSubmitted, datastore.StatusInterview} {
+		if err := ds.SetJobPostingStatus(ctx, "linkedin", "1", status); err != nil {
+			t.Fatalf("failed to set job posting status to %s: %v", status, err)
+		}
+	}
+
+	err := ds.SetJobPostingStatus(ctx, "linkedin", "1", datastore.StatusQueued)
+	if !errors.Is(err, datastore.ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+
+	err = ds.SetJobPostingStatus(ctx, "linkedin", "3", datastore.StatusQueued)
+	if !errors.Is(err, datastore.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	history, err := ds.GetJobPostingHistory(ctx, "linkedin", "1")
+	if err != nil {
+		t.Fatalf("failed to get job posting history: %v", err)
+	}
+	want := []string{
+		datastore.StatusDiscovered,
+		datastore.StatusQueued,
+		datastore.StatusApplying,
+		datastore.StatusSubmitted,
+		datastore.StatusInterview,
+	}
+	if len(history) != len(want) {
+		t.Fatalf("expected %d status changes, got %d", len(want), len(history))
+	}
+	for i, change := range history {
+		if change.To != want[i] || (i > 0 && change.From != want[i-1]) {
+			t.Fatalf("unexpected status change %d: %+v", i, change)
+		}
+	}
+
+	funnel, err := ds.GetStatusFunnel(ctx)
+	if err != nil {
+		t.Fatalf("failed to get status funnel: %v", err)
+	}
+	if funnel[datastore.StatusDiscovered] != 2 || funnel[datastore.StatusSubmitted] != 1 || funnel[datastore.StatusFiltered] != 1 {
+		t.Fatalf("unexpected funnel: %v", funnel)
+	}
+
+	counts, err := ds.GetStatusCounts(ctx)
+	if err != nil {
+		t.Fatalf("failed to get status counts: %v", err)
+	}
+	if counts[datastore.StatusInterview] != 1 || counts[datastore.StatusSubmitted] != 0 {
+		t.Fatalf("unexpected status counts: %v", counts)
+	}
+}
@@ -0,0 +1,68 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k1ng440/job-bot/internal/config"
+)
+
+// New opens the datastore selected by the config, defaulting to sqlite.
+func New(cfg config.Datastore) (Datastore, error) {
+	switch cfg.Driver {
+	case "", config.DatastoreSqlite:
+		return NewSqliteDatastore(cfg.DSN)
+	case config.DatastorePostgres:
+		return NewPostgresDatastore(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown datastore driver %q", cfg.Driver)
+	}
+}
+
+// MigrationStatus returns every migration of the configured datastore,
+// with AppliedAt set on the applied ones.
+func MigrationStatus(ctx context.Context, cfg config.Datastore) ([]Migration, error) {
+	switch cfg.Driver {
+	case "", config.DatastoreSqlite:
+		return SqliteMigrationStatus(ctx, cfg.DSN)
+	case config.DatastorePostgres:
+		return PostgresMigrationStatus(ctx, cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown datastore driver %q", cfg.Driver)
+	}
+}
+
+// Migrate runs the pending migrations of the configured datastore and returns them.
+func Migrate(ctx context.Context, cfg config.Datastore) ([]Migration, error) {
+	switch cfg.Driver {
+	case "", config.DatastoreSqlite:
+		return MigrateSqlite(ctx, cfg.DSN)
+	case config.DatastorePostgres:
+		return MigratePostgres(ctx, cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown datastore driver %q", cfg.Driver)
+	}
+}
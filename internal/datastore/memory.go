@@ -0,0 +1,561 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ Datastore = (*memoryStore)(nil)
+
+// jobKey identifies a job posting, like the (platform, id) primary key.
+type jobKey struct {
+	platform string
+	id       string
+}
+
+// statusRecord is a row of the status history.
+type statusRecord struct {
+	key    jobKey
+	change StatusChange
+}
+
+// memoryStore is a Datastore kept in memory. It behaves like the sql
+// datastores and is used by tests and dry runs. Everything is lost on Close.
+type memoryStore struct {
+	mu sync.Mutex
+
+	// postings are kept in insertion order so ties sort like in sqlite
+	postings  []*JobPosting
+	index     map[jobKey]*JobPosting
+	history   []statusRecord
+	applied   map[[2]string]int
+	byCompany map[[2]string]int
+
+	questions    []*Question
+	jobQuestions map[jobKey]map[int64]bool
+
+	checkpoints map[[2]string]*Checkpoint
+}
+
+// NewMemoryDatastore creates an empty in-memory datastore.
+func NewMemoryDatastore() Datastore {
+	return &memoryStore{
+		index:        map[jobKey]*JobPosting{},
+		applied:      map[[2]string]int{},
+		byCompany:    map[[2]string]int{},
+		jobQuestions: map[jobKey]map[int64]bool{},
+		checkpoints:  map[[2]string]*Checkpoint{},
+	}
+}
+
+// copyJobPosting returns a copy of the job posting, so callers cannot
+// change the stored one.
+func copyJobPosting(p *JobPosting) *JobPosting {
+	c := *p
+	c.PostedAt = p.PostedAt.UTC()
+	if p.ScoreDetails != nil {
+		c.ScoreDetails = append([]ScoreContribution{}, p.ScoreDetails...)
+	}
+	return &c
+}
+
+func copyJobPostings(posts []*JobPosting) []*JobPosting {
+	var c []*JobPosting
+	for _, p := range posts {
+		c = append(c, copyJobPosting(p))
+	}
+	return c
+}
+
+// GetAppliedCountByCompany implements Datastore.
+func (m *memoryStore) GetAppliedCountByCompany(_ context.Context, name string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.byCompany[[2]string{name, today()}], nil
+}
+
+// GetAppliedTodayCount implements Datastore.
+// It returns the number of applications sent today on the platforms, or on
+// every platform when none is given.
+func (m *memoryStore) GetAppliedTodayCount(_ context.Context, platforms ...string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count, date := 0, today()
+	for key, n := range m.applied {
+		if key[1] == date && onPlatform(key[0], platforms) {
+			count += n
+		}
+	}
+
+	return count, nil
+}
+
+// IncAppliedTodayCount increases the applied count for a platform.
+func (m *memoryStore) IncAppliedTodayCount(_ context.Context, platform string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.applied[[2]string{platform, today()}]++
+	return nil
+}
+
+// IncAppliedCountByCompany increases the applied count for a company.
+func (m *memoryStore) IncAppliedCountByCompany(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.byCompany[[2]string{name, today()}]++
+	return nil
+}
+
+// GetAppliedTodayCountBySearch returns the number of applications sent today
+// for job postings of the platform found by the search.
+func (m *memoryStore) GetAppliedTodayCountBySearch(_ context.Context, platform, search string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	midnight := time.Now().UTC().Truncate(24 * time.Hour)
+	submitted := map[jobKey]bool{}
+	for _, r := range m.history {
+		if r.change.To == StatusSubmitted && !r.change.ChangedAt.Before(midnight) &&
+			r.key.platform == platform && m.index[r.key].Search == search {
+			submitted[r.key] = true
+		}
+	}
+
+	return len(submitted), nil
+}
+
+// InsertJobPosting stores a job posting and starts its status history.
+// Job postings without a status are queued. It returns ErrAlreadyExists
+// when the job posting is already stored.
+func (m *memoryStore) InsertJobPosting(_ context.Context, jobPosting *JobPosting) error {
+	if jobPosting.Status == "" {
+		jobPosting.Status = StatusQueued
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := jobKey{jobPosting.Platform, jobPosting.ID}
+	if _, ok := m.index[key]; ok {
+		return ErrAlreadyExists
+	}
+
+	post := copyJobPosting(jobPosting)
+	m.postings = append(m.postings, post)
+	m.index[key] = post
+
+	// Every job posting is discovered first, then moves on to its status
+	m.recordStatus(key, "", StatusDiscovered)
+	if post.Status != StatusDiscovered {
+		m.recordStatus(key, StatusDiscovered, post.Status)
+	}
+
+	return nil
+}
+
+// SetJobPostingStatus moves the job posting to the status and records the
+// change in its history. It returns ErrInvalidTransition when the current
+// status cannot move to the new one. Moving to applying claims the job:
+// only one caller succeeds, the others get ErrTaken.
+func (m *memoryStore) SetJobPostingStatus(_ context.Context, platform, id, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.setStatus(jobKey{platform, id}, status)
+}
+
+// setStatus moves the job posting to the status. The lock must be held.
+func (m *memoryStore) setStatus(key jobKey, status string) error {
+	post, ok := m.index[key]
+	if !ok {
+		return ErrNotFound
+	}
+
+	from := post.Status
+	if from == StatusApplying && status == StatusApplying {
+		return ErrTaken
+	}
+	if from == status {
+		return nil
+	}
+	if !CanTransition(from, status) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, from, status)
+	}
+
+	post.Status = status
+	m.recordStatus(key, from, status)
+	return nil
+}
+
+// recordStatus adds a status change to the history. The lock must be held.
+func (m *memoryStore) recordStatus(key jobKey, from, to string) {
+	m.history = append(m.history, statusRecord{
+		key:    key,
+		change: StatusChange{From: from, To: to, ChangedAt: time.Now().UTC()},
+	})
+}
+
+// GetJobPostingHistory returns the status changes of the job posting, oldest first.
+func (m *memoryStore) GetJobPostingHistory(_ context.Context, platform, id string) ([]*StatusChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var history []*StatusChange
+	for _, r := range m.history {
+		if r.key == (jobKey{platform, id}) {
+			change := r.change
+			history = append(history, &change)
+		}
+	}
+
+	return history, nil
+}
+
+// GetStatusCounts returns the number of job postings currently in each status.
+func (m *memoryStore) GetStatusCounts(_ context.Context) (map[string]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, post := range m.postings {
+		counts[post.Status]++
+	}
+
+	return counts, nil
+}
+
+// GetStatusFunnel returns the number of job postings that ever reached each status.
+func (m *memoryStore) GetStatusFunnel(_ context.Context) (map[string]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reached := map[string]map[jobKey]bool{}
+	for _, r := range m.history {
+		if reached[r.change.To] == nil {
+			reached[r.change.To] = map[jobKey]bool{}
+		}
+		reached[r.change.To][r.key] = true
+	}
+
+	funnel := map[string]int{}
+	for status, keys := range reached {
+		funnel[status] = len(keys)
+	}
+
+	return funnel, nil
+}
+
+// GetUnappliedJobPosting returns a job posting that has not been applied to yet.
+// Only queued postings are considered. The best scoring and most recent one is picked.
+// Only job postings of the platforms are considered, or of every platform
+// when none is given. If there are no unapplied job postings, nil is returned.
+func (m *memoryStore) GetUnappliedJobPosting(ctx context.Context, platforms ...string) (*JobPosting, error) {
+	queue, err := m.GetUnappliedJobPostings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range queue {
+		if onPlatform(post.Platform, platforms) {
+			return post, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// onPlatform reports whether the platform is one of the platforms, or true
+// when no platform is given.
+func onPlatform(platform string, platforms []string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+
+	for _, p := range platforms {
+		if platform == p {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUnappliedJobPostings returns the queue of job postings in the order
+// GetUnappliedJobPosting picks them.
+func (m *memoryStore) GetUnappliedJobPostings(_ context.Context) ([]*JobPosting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var queue []*JobPosting
+	for _, post := range m.postings {
+		if post.Status == StatusQueued {
+			queue = append(queue, copyJobPosting(post))
+		}
+	}
+
+	// Best scoring first, then most recent, with unknown posting times last
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := queue[i], queue[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.PostedAt.IsZero() || b.PostedAt.IsZero() {
+			return !a.PostedAt.IsZero() && b.PostedAt.IsZero()
+		}
+		return a.PostedAt.After(b.PostedAt)
+	})
+
+	return queue, nil
+}
+
+// RequeueJobPostings puts the job postings with one of the statuses back
+// in the queue and returns how many were requeued.
+func (m *memoryStore) RequeueJobPostings(_ context.Context, statuses ...string) (int, error) {
+	requeue := map[string]bool{}
+	for _, status := range statuses {
+		if !CanTransition(status, StatusQueued) {
+			return 0, fmt.Errorf("%w from %s to %s", ErrInvalidTransition, status, StatusQueued)
+		}
+		requeue[status] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.requeue(func(post *JobPosting) bool {
+		return requeue[post.Status]
+	})
+}
+
+// RequeueStaleJobPostings puts the job postings that moved to the status
+// before the given time, and still have it, back in the queue and returns
+// how many were requeued.
+func (m *memoryStore) RequeueStaleJobPostings(_ context.Context, status string, before time.Time) (int, error) {
+	if !CanTransition(status, StatusQueued) {
+		return 0, fmt.Errorf("%w from %s to %s", ErrInvalidTransition, status, StatusQueued)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The last change to the status of each job posting
+	changed := map[jobKey]time.Time{}
+	for _, r := range m.history {
+		if r.change.To == status {
+			changed[r.key] = r.change.ChangedAt
+		}
+	}
+
+	return m.requeue(func(post *JobPosting) bool {
+		at, ok := changed[jobKey{post.Platform, post.ID}]
+		return post.Status == status && ok && at.Before(before)
+	})
+}
+
+// requeue puts the job postings selected by requeue back in the queue.
+// The lock must be held.
+func (m *memoryStore) requeue(requeue func(post *JobPosting) bool) (int, error) {
+	n := 0
+	for _, post := range m.postings {
+		if !requeue(post) {
+			continue
+		}
+		if err := m.setStatus(jobKey{post.Platform, post.ID}, StatusQueued); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// GetJobPostings returns every stored job posting, best scoring first.
+func (m *memoryStore) GetJobPostings(_ context.Context) ([]*JobPosting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	posts := copyJobPostings(m.postings)
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].Score > posts[j].Score
+	})
+
+	return posts, nil
+}
+
+// GetRetryableJobPostings returns the job postings waiting for answers
+// whose questions have all been answered since. Only job postings of the
+// platforms are considered, or of every platform when none is given.
+func (m *memoryStore) GetRetryableJobPostings(_ context.Context, platforms ...string) ([]*JobPosting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var retryable []*JobPosting
+	for _, post := range m.postings {
+		if post.Status != StatusNeedsAnswer || !onPlatform(post.Platform, platforms) {
+			continue
+		}
+
+		answered := true
+		for id := range m.jobQuestions[jobKey{post.Platform, post.ID}] {
+			if !m.questions[id-1].Answered {
+				answered = false
+				break
+			}
+		}
+		if answered {
+			retryable = append(retryable, copyJobPosting(post))
+		}
+	}
+
+	return retryable, nil
+}
+
+// InsertQuestion stores an unanswered question and links it to the job posting
+// it was asked for. Questions are unique by label, so a question asked by
+// several job postings only has to be answered once.
+func (m *memoryStore) InsertQuestion(_ context.Context, platform, jobID string, question *Question) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stored *Question
+	for _, q := range m.questions {
+		if q.Label == question.Label {
+			stored = q
+			break
+		}
+	}
+	if stored == nil {
+		// Ids start at 1 and index questions, like an autoincrement column
+		stored = &Question{
+			ID:      int64(len(m.questions) + 1),
+			Label:   question.Label,
+			Kind:    question.Kind,
+			Options: append([]string{}, question.Options...),
+		}
+		m.questions = append(m.questions, stored)
+	}
+	question.ID, question.Answer, question.Answered = stored.ID, stored.Answer, stored.Answered
+
+	key := jobKey{platform, jobID}
+	if m.jobQuestions[key] == nil {
+		m.jobQuestions[key] = map[int64]bool{}
+	}
+	m.jobQuestions[key][stored.ID] = true
+
+	return nil
+}
+
+// GetQuestions returns every stored question, answered or not.
+func (m *memoryStore) GetQuestions(_ context.Context) ([]*Question, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var questions []*Question
+	for _, q := range m.questions {
+		c := *q
+		c.Options = append([]string{}, q.Options...)
+		questions = append(questions, &c)
+	}
+
+	return questions, nil
+}
+
+// AnswerQuestion stores the answer to a question.
+func (m *memoryStore) AnswerQuestion(_ context.Context, id int64, answer string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id < 1 || id > int64(len(m.questions)) {
+		return ErrNotFound
+	}
+	m.questions[id-1].Answer = answer
+	m.questions[id-1].Answered = true
+
+	return nil
+}
+
+// GetCheckpoint returns the crawl checkpoint of the search url,
+// or ErrNotFound when there is none.
+func (m *memoryStore) GetCheckpoint(_ context.Context, platform, url string) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	checkpoint, ok := m.checkpoints[[2]string{platform, url}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	c := *checkpoint
+	c.JobIDs = append([]string{}, checkpoint.JobIDs...)
+	return &c, nil
+}
+
+// SaveCheckpoint stores the crawl checkpoint of a search url,
+// replacing the previous one. UpdatedAt is set to the current time.
+func (m *memoryStore) SaveCheckpoint(_ context.Context, checkpoint *Checkpoint) error {
+	if checkpoint.JobIDs == nil {
+		checkpoint.JobIDs = []string{}
+	}
+	checkpoint.UpdatedAt = time.Now().UTC()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := *checkpoint
+	c.JobIDs = append([]string{}, checkpoint.JobIDs...)
+	m.checkpoints[[2]string{c.Platform, c.Url}] = &c
+
+	return nil
+}
+
+// DeleteCheckpoint removes the crawl checkpoint of a search url.
+func (m *memoryStore) DeleteCheckpoint(_ context.Context, platform, url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.checkpoints, [2]string{platform, url})
+	return nil
+}
+
+// DeleteCheckpoints removes every crawl checkpoint.
+func (m *memoryStore) DeleteCheckpoints(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.checkpoints = map[[2]string]*Checkpoint{}
+	return nil
+}
+
+// Close implements Datastore. There is nothing to release.
+func (m *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,382 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Migration is a numbered change to the database schema. Migrations run in
+// order, each in its own transaction, and are recorded in schema_version.
+type Migration struct {
+	Version     int
+	Description string
+	// AppliedAt is when the migration ran, or the zero time when it is pending
+	AppliedAt time.Time
+
+	up func(ctx context.Context, tx *sql.Tx) error
+}
+
+// schema is the migration history of a kind of database.
+type schema struct {
+	migrations []Migration
+	// createVersionTable creates the schema_version table
+	createVersionTable string
+	rebind             func(query string) string
+	// backup is called with the current version before migrating, if set
+	backup func(ctx context.Context, db *sql.DB, version int) error
+	// lock and unlock are run on a connection of their own around reading
+	// the version and migrating, if set, so bots sharing the database do
+	// not run the same migration at once
+	lock, unlock string
+}
+
+// sqliteMigrations is the history of the sqlite schema. Never edit a
+// migration once released, add a new one instead. Migrations must also
+// work on databases created before schema_version existed, when every
+// table was created with CREATE TABLE IF NOT EXISTS.
+var sqliteMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "initial schema",
+		up: execQuery(`
+			CREATE TABLE IF NOT EXISTS job_postings (
+				platform TEXT,
+				id TEXT,
+				url TEXT,
+				job_title TEXT,
+				company TEXT,
+				applied INTEGER,
+				PRIMARY KEY (platform, id)
+			);
+
+			CREATE TABLE IF NOT EXISTS applied_counts (
+				platform TEXT,
+				date TEXT,
+				count INTEGER,
+				PRIMARY KEY (platform, date)
+			);
+
+			CREATE TABLE IF NOT EXISTS applied_counts_by_company (
+				name TEXT,
+				date TEXT,
+				count INTEGER,
+				PRIMARY KEY (name, date)
+			);
+		`),
+	},
+	{
+		Version:     2,
+		Description: "job posting details, filter reason and score",
+		up: addColumns("job_postings", [][2]string{
+			{"status", "TEXT NOT NULL DEFAULT ''"},
+			{"posted_at", "DATETIME"},
+			{"filter_reason", "TEXT NOT NULL DEFAULT ''"},
+			{"location", "TEXT NOT NULL DEFAULT ''"},
+			{"seniority", "TEXT NOT NULL DEFAULT ''"},
+			{"score", "REAL NOT NULL DEFAULT 0"},
+			{"score_details", "TEXT NOT NULL DEFAULT '[]'"},
+			{"workplace_type", "TEXT NOT NULL DEFAULT ''"},
+			{"employment_type", "TEXT NOT NULL DEFAULT ''"},
+			{"applicant_count", "INTEGER NOT NULL DEFAULT 0"},
+			{"salary", "TEXT NOT NULL DEFAULT ''"},
+			{"hiring_team", "TEXT NOT NULL DEFAULT ''"},
+			{"description", "TEXT NOT NULL DEFAULT ''"},
+		}),
+	},
+	{
+		Version:     3,
+		Description: "application questions",
+		up: execQuery(`
+			CREATE TABLE IF NOT EXISTS questions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				label TEXT UNIQUE,
+				kind TEXT,
+				options TEXT,
+				answer TEXT NOT NULL DEFAULT '',
+				answered INTEGER NOT NULL DEFAULT 0
+			);
+
+			CREATE TABLE IF NOT EXISTS job_questions (
+				platform TEXT,
+				job_id TEXT,
+				question_id INTEGER,
+				PRIMARY KEY (platform, job_id, question_id)
+			);
+		`),
+	},
+	{
+		Version:     4,
+		Description: "search checkpoints",
+		up: execQuery(`
+			CREATE TABLE IF NOT EXISTS search_checkpoints (
+				platform TEXT,
+				url TEXT,
+				start INTEGER NOT NULL DEFAULT 0,
+				job_ids TEXT NOT NULL DEFAULT '[]',
+				updated_at DATETIME,
+				PRIMARY KEY (platform, url)
+			);
+		`),
+	},
+	{
+		Version:     5,
+		Description: "application lifecycle",
+		up:          migrateLifecycle,
+	},
+	{
+		Version:     6,
+		Description: "job posting search",
+		up: addColumns("job_postings", [][2]string{
+			{"search", "TEXT NOT NULL DEFAULT ''"},
+		}),
+	},
+	{
+		Version:     7,
+		Description: "job posting apply url",
+		up: addColumns("job_postings", [][2]string{
+			{"apply_url", "TEXT NOT NULL DEFAULT ''"},
+		}),
+	},
+}
+
+// migrateLifecycle replaces the applied flag with statuses and starts the
+// status history of the existing job postings.
+func migrateLifecycle(ctx context.Context, tx *sql.Tx) error {
+	if err := execQuery(`
+		CREATE TABLE IF NOT EXISTS job_status_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			platform TEXT,
+			job_id TEXT,
+			from_status TEXT NOT NULL DEFAULT '',
+			to_status TEXT,
+			changed_at DATETIME
+		);
+	`)(ctx, tx); err != nil {
+		return err
+	}
+
+	applied, err := hasColumn(ctx, tx, "job_postings", "applied")
+	if err != nil {
+		return err
+	}
+	if applied {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE job_postings SET status = ? WHERE applied = 1`, StatusSubmitted,
+		); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE job_postings DROP COLUMN applied`); err != nil {
+			return err
+		}
+	}
+
+	return execQuery(`
+		UPDATE job_postings SET status = '`+StatusFailed+`' WHERE status = 'abandoned';
+		UPDATE job_postings SET status = '`+StatusQueued+`' WHERE status = '';
+
+		CREATE TEMP TABLE unseeded AS
+			SELECT platform, id, status
+			FROM job_postings p
+			WHERE NOT EXISTS (
+				SELECT 1 FROM job_status_history h WHERE h.platform = p.platform AND h.job_id = p.id
+			);
+
+		INSERT INTO job_status_history (platform, job_id, from_status, to_status, changed_at)
+			SELECT platform, id, '', '`+StatusDiscovered+`', CURRENT_TIMESTAMP FROM unseeded;
+
+		INSERT INTO job_status_history (platform, job_id, from_status, to_status, changed_at)
+			SELECT platform, id, '`+StatusDiscovered+`', status, CURRENT_TIMESTAMP
+			FROM unseeded
+			WHERE status != '`+StatusDiscovered+`';
+
+		DROP TABLE unseeded;
+	`)(ctx, tx)
+}
+
+func execQuery(query string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query)
+		return err
+	}
+}
+
+// addColumns adds the columns missing from the table, given as name and definition.
+func addColumns(table string, columns [][2]string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		for _, c := range columns {
+			ok, err := hasColumn(ctx, tx, table, c[0])
+			if err != nil {
+				return err
+			}
+			if ok {
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, `ALTER TABLE `+table+` ADD COLUMN `+c[0]+` `+c[1]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func hasColumn(ctx context.Context, tx *sql.Tx, table, column string) (bool, error) {
+	var n int
+	row := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column)
+	if err := row.Scan(&n); err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// migrationStatus returns every migration, with AppliedAt set on the applied ones.
+func migrationStatus(ctx context.Context, db *sql.DB, s schema) ([]Migration, error) {
+	if _, err := db.ExecContext(ctx, s.createVersionTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at FROM schema_version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, len(s.migrations))
+	copy(migrations, s.migrations)
+	for i := range migrations {
+		migrations[i].AppliedAt = applied[migrations[i].Version]
+	}
+
+	return migrations, nil
+}
+
+// lockSchema takes the migration lock of the schema, if it has one, and
+// returns the function releasing it.
+func lockSchema(ctx context.Context, db *sql.DB, s schema) (func(), error) {
+	if s.lock == "" {
+		return func() {}, nil
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, s.lock); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), s.unlock); err != nil {
+			log.Warn().Err(err).Msg("Failed to release migration lock")
+		}
+		conn.Close()
+	}, nil
+}
+
+// migrate runs the pending migrations and returns them.
+func migrate(ctx context.Context, db *sql.DB, s schema) ([]Migration, error) {
+	unlock, err := lockSchema(ctx, db, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock schema. %w", err)
+	}
+	defer unlock()
+
+	migrations, err := migrationStatus(ctx, db, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version. %w", err)
+	}
+
+	var pending []Migration
+	current := 0
+	for _, m := range migrations {
+		if m.AppliedAt.IsZero() {
+			pending = append(pending, m)
+		} else {
+			current = m.Version
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	if s.backup != nil {
+		if err := s.backup(ctx, db, current); err != nil {
+			return nil, fmt.Errorf("failed to back up database. %w", err)
+		}
+	}
+
+	for i := range pending {
+		m := &pending[i]
+		log.Info().Int("version", m.Version).Str("description", m.Description).Msg("Migrating database")
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.up(ctx, tx); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to run migration %d. %w", m.Version, err)
+		}
+
+		m.AppliedAt = time.Now().UTC()
+		if _, err := tx.ExecContext(ctx,
+			s.rebind(`INSERT INTO schema_version (version, description, applied_at) VALUES (?, ?, ?)`),
+			m.Version, m.Description, m.AppliedAt,
+		); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	return pending, nil
+}
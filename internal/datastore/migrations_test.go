@@ -0,0 +1,109 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	defer os.Remove(testDBFile)
+	defer os.Remove(testDBFile + ".v0.bak")
+
+	// A database created before migrations existed
+	db, err := sql.Open("sqlite3", testDBFile)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE job_postings (
+			platform TEXT,
+			id TEXT,
+			url TEXT,
+			job_title TEXT,
+			company TEXT,
+			applied INTEGER,
+			PRIMARY KEY (platform, id)
+		);
+		INSERT INTO job_postings VALUES ('linkedin', '1', 'https://example.com/1', 'Go Developer', 'Acme', 1);
+		INSERT INTO job_postings VALUES ('linkedin', '2', 'https://example.com/2', 'SRE', 'Acme', 0);
+	`); err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+	db.Close()
+
+	ds, err := datastore.NewSqliteDatastore(testDBFile)
+	if err != nil {
+		t.Fatalf("failed to migrate legacy database: %v", err)
+	}
+	defer ds.Close()
+
+	if _, err := os.Stat(testDBFile + ".v0.bak"); err != nil {
+		t.Fatalf("expected a backup of the legacy database: %v", err)
+	}
+
+	posts, err := ds.GetJobPostings(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get job postings: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, post := range posts {
+		statuses[post.ID] = post.Status
+	}
+	if statuses["1"] != datastore.StatusSubmitted || statuses["2"] != datastore.StatusQueued {
+		t.Fatalf("unexpected statuses after migrating: %v", statuses)
+	}
+
+	history, err := ds.GetJobPostingHistory(context.Background(), "linkedin", "1")
+	if err != nil {
+		t.Fatalf("failed to get job posting history: %v", err)
+	}
+	if len(history) != 2 || history[1].To != datastore.StatusSubmitted {
+		t.Fatalf("unexpected history after migrating: %+v", history)
+	}
+
+	migrations, err := datastore.SqliteMigrationStatus(context.Background(), testDBFile)
+	if err != nil {
+		t.Fatalf("failed to get migration status: %v", err)
+	}
+	for _, m := range migrations {
+		if m.AppliedAt.IsZero() {
+			t.Fatalf("migration %d was not applied", m.Version)
+		}
+	}
+
+	// Migrating again is a no-op
+	applied, err := datastore.MigrateSqlite(context.Background(), testDBFile)
+	if err != nil {
+		t.Fatalf("failed to migrate again: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("expected no pending migrations, got %d", len(applied))
+	}
+}
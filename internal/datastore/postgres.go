@@ -0,0 +1,197 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq" // Import the PostgreSQL driver
+)
+
+const createPostgresVersionTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		applied_at TIMESTAMPTZ
+	)
+`
+
+// postgresMigrations is the history of the postgres schema. It started out
+// at the schema of sqlite migration 5, so changes to the job postings need
+// a migration in both lists.
+var postgresMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "initial schema",
+		up: execQuery(`
+			CREATE TABLE IF NOT EXISTS job_postings (
+				platform TEXT,
+				id TEXT,
+				url TEXT,
+				job_title TEXT,
+				company TEXT,
+				status TEXT NOT NULL DEFAULT '',
+				posted_at TIMESTAMPTZ,
+				filter_reason TEXT NOT NULL DEFAULT '',
+				location TEXT NOT NULL DEFAULT '',
+				seniority TEXT NOT NULL DEFAULT '',
+				score DOUBLE PRECISION NOT NULL DEFAULT 0,
+				score_details TEXT NOT NULL DEFAULT '[]',
+				workplace_type TEXT NOT NULL DEFAULT '',
+				employment_type TEXT NOT NULL DEFAULT '',
+				applicant_count INTEGER NOT NULL DEFAULT 0,
+				salary TEXT NOT NULL DEFAULT '',
+				hiring_team TEXT NOT NULL DEFAULT '',
+				description TEXT NOT NULL DEFAULT '',
+				PRIMARY KEY (platform, id)
+			);
+
+			CREATE TABLE IF NOT EXISTS applied_counts (
+				platform TEXT,
+				date TEXT,
+				count INTEGER,
+				PRIMARY KEY (platform, date)
+			);
+
+			CREATE TABLE IF NOT EXISTS applied_counts_by_company (
+				name TEXT,
+				date TEXT,
+				count INTEGER,
+				PRIMARY KEY (name, date)
+			);
+
+			CREATE TABLE IF NOT EXISTS questions (
+				id BIGSERIAL PRIMARY KEY,
+				label TEXT UNIQUE,
+				kind TEXT,
+				options TEXT,
+				answer TEXT NOT NULL DEFAULT '',
+				answered INTEGER NOT NULL DEFAULT 0
+			);
+
+			CREATE TABLE IF NOT EXISTS job_questions (
+				platform TEXT,
+				job_id TEXT,
+				question_id BIGINT,
+				PRIMARY KEY (platform, job_id, question_id)
+			);
+
+			CREATE TABLE IF NOT EXISTS search_checkpoints (
+				platform TEXT,
+				url TEXT,
+				start INTEGER NOT NULL DEFAULT 0,
+				job_ids TEXT NOT NULL DEFAULT '[]',
+				updated_at TIMESTAMPTZ,
+				PRIMARY KEY (platform, url)
+			);
+
+			CREATE TABLE IF NOT EXISTS job_status_history (
+				id BIGSERIAL PRIMARY KEY,
+				platform TEXT,
+				job_id TEXT,
+				from_status TEXT NOT NULL DEFAULT '',
+				to_status TEXT,
+				changed_at TIMESTAMPTZ
+			);
+		`),
+	},
+	{
+		Version:     2,
+		Description: "job posting search",
+		up:          execQuery(`ALTER TABLE job_postings ADD COLUMN IF NOT EXISTS search TEXT NOT NULL DEFAULT ''`),
+	},
+	{
+		Version:     3,
+		Description: "job posting apply url",
+		up:          execQuery(`ALTER TABLE job_postings ADD COLUMN IF NOT EXISTS apply_url TEXT NOT NULL DEFAULT ''`),
+	},
+}
+
+var postgresSchema = schema{
+	migrations:         postgresMigrations,
+	createVersionTable: createPostgresVersionTableQuery,
+	rebind:             rebindPostgres,
+	// The advisory lock key is arbitrary, but must be the same for every bot
+	lock:   `SELECT pg_advisory_lock(7346240)`,
+	unlock: `SELECT pg_advisory_unlock(7346240)`,
+}
+
+// rebindPostgres rewrites the ? placeholders of a query to $1, $2 and so on.
+// Queries must not contain a literal ?.
+func rebindPostgres(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// NewPostgresDatastore creates a new PostgreSQL-based datastore and migrates
+// its schema to the latest version. Several bots can share the database.
+func NewPostgresDatastore(dsn string) (Datastore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := migrate(context.Background(), db, postgresSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqlStore{db: &sqlDB{DB: db, rebind: postgresSchema.rebind}}, nil
+}
+
+// PostgresMigrationStatus returns every migration of the postgres database,
+// with AppliedAt set on the applied ones.
+func PostgresMigrationStatus(ctx context.Context, dsn string) ([]Migration, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return migrationStatus(ctx, db, postgresSchema)
+}
+
+// MigratePostgres runs the pending migrations of the postgres database and returns them.
+func MigratePostgres(ctx context.Context, dsn string) ([]Migration, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return migrate(ctx, db, postgresSchema)
+}
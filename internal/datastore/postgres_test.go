@@ -0,0 +1,224 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+// testPostgresDSN names the environment variable holding the connection
+// string of a postgres database to test against. When it is empty a
+// throwaway server is started with the initdb and pg_ctl binaries.
+const testPostgresDSN = "JB_TEST_POSTGRES_DSN"
+
+// testPostgres is the throwaway postgres server shared by the tests.
+var testPostgres struct {
+	once sync.Once
+	dir  string
+	dsn  string
+	err  error
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	stopPostgres()
+	os.Exit(code)
+}
+
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv(testPostgresDSN)
+	if dsn == "" {
+		testPostgres.once.Do(startPostgres)
+		if testPostgres.err != nil {
+			t.Skipf("postgres is not available, set %s to test against a running server: %v", testPostgresDSN, testPostgres.err)
+		}
+		dsn = testPostgres.dsn
+	}
+
+	runConformance(t, func(t *testing.T) (datastore.Datastore, func()) {
+		resetPostgres(t, dsn)
+
+		ds, err := datastore.NewPostgresDatastore(dsn)
+		if err != nil {
+			t.Fatalf("failed to open test database: %v", err)
+		}
+
+		return ds, func() { ds.Close() }
+	})
+
+	t.Run("ClaimRace", func(t *testing.T) {
+		resetPostgres(t, dsn)
+
+		a, err := datastore.NewPostgresDatastore(dsn)
+		if err != nil {
+			t.Fatalf("failed to open test database: %v", err)
+		}
+		defer a.Close()
+
+		b, err := datastore.NewPostgresDatastore(dsn)
+		if err != nil {
+			t.Fatalf("failed to open test database: %v", err)
+		}
+		defer b.Close()
+
+		testClaimRace(t, a, b)
+	})
+
+	t.Run("MigrateRace", func(t *testing.T) {
+		resetPostgres(t, dsn)
+
+		// Bots started together all migrate the empty database
+		var wg sync.WaitGroup
+		errs := make(chan error, 4)
+		for i := 0; i < cap(errs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ds, err := datastore.NewPostgresDatastore(dsn)
+				if err == nil {
+					ds.Close()
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("failed to open test database: %v", err)
+			}
+		}
+
+		migrations, err := datastore.PostgresMigrationStatus(context.Background(), dsn)
+		if err != nil {
+			t.Fatalf("failed to get migration status: %v", err)
+		}
+		for _, m := range migrations {
+			if m.AppliedAt.IsZero() {
+				t.Fatalf("expected migration %d to be applied", m.Version)
+			}
+		}
+	})
+}
+
+// resetPostgres drops every table so each test starts from an empty schema.
+func resetPostgres(t *testing.T, dsn string) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`DROP SCHEMA public CASCADE; CREATE SCHEMA public`); err != nil {
+		t.Fatalf("failed to reset test database: %v", err)
+	}
+}
+
+// startPostgres initializes a database cluster in a temporary directory
+// and starts a server for it on a free port.
+func startPostgres() {
+	initdb, err := postgresBinary("initdb")
+	if err != nil {
+		testPostgres.err = err
+		return
+	}
+	pgCtl := filepath.Join(filepath.Dir(initdb), "pg_ctl")
+
+	dir, err := os.MkdirTemp("", "jb-postgres")
+	if err != nil {
+		testPostgres.err = err
+		return
+	}
+	testPostgres.dir = dir
+
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "jb", "-A", "trust", "--no-sync").CombinedOutput(); err != nil {
+		testPostgres.err = fmt.Errorf("initdb failed. %w: %s", err, out)
+		return
+	}
+
+	port, err := freePort()
+	if err != nil {
+		testPostgres.err = err
+		return
+	}
+
+	opts := fmt.Sprintf("-h 127.0.0.1 -p %d -k %s -F", port, dir)
+	if out, err := exec.Command(pgCtl, "start", "-w", "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-o", opts).CombinedOutput(); err != nil {
+		testPostgres.err = fmt.Errorf("pg_ctl failed. %w: %s", err, out)
+		return
+	}
+
+	testPostgres.dsn = fmt.Sprintf("postgres://jb@127.0.0.1:%d/postgres?sslmode=disable", port)
+}
+
+// stopPostgres stops the throwaway server, if one was started.
+func stopPostgres() {
+	if testPostgres.dir == "" {
+		return
+	}
+
+	if testPostgres.dsn != "" {
+		if pgCtl, err := postgresBinary("pg_ctl"); err == nil {
+			exec.Command(pgCtl, "stop", "-m", "immediate", "-D", filepath.Join(testPostgres.dir, "data")).Run()
+		}
+	}
+	os.RemoveAll(testPostgres.dir)
+}
+
+// postgresBinary looks up a postgres server binary in PATH and in the
+// directories used by the debian packages.
+func postgresBinary(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql/*/bin", name))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%s not found", name)
+	}
+
+	return matches[len(matches)-1], nil
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
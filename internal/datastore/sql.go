@@ -0,0 +1,807 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package datastore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// queuedJobPostings selects the job postings waiting to be applied to.
+const queuedJobPostings = `status = '` + StatusQueued + `'`
+
+// queueOrder sorts the queue best scoring and most recent first.
+const queueOrder = ` ORDER BY score DESC, posted_at DESC NULLS LAST`
+
+// jobPostingColumns lists the job_postings columns in the order read by scanJobPosting.
+const jobPostingColumns = `platform, id, url, job_title, company, status, posted_at, filter_reason,
+	location, seniority, score, score_details, workplace_type, employment_type, applicant_count,
+	salary, hiring_team, description, search, apply_url`
+
+var _ Datastore = (*sqlStore)(nil)
+
+// sqlStore is the Datastore implementation shared by the SQL databases.
+// Queries are written for sqlite and use ? placeholders, which are
+// rewritten for the driver in use.
+type sqlStore struct {
+	db *sqlDB
+}
+
+// sqlDB wraps sql.DB to rewrite the placeholders of every query.
+type sqlDB struct {
+	*sql.DB
+	rebind func(query string) string
+}
+
+func (db *sqlDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.DB.ExecContext(ctx, db.rebind(query), args...)
+}
+
+func (db *sqlDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.DB.QueryContext(ctx, db.rebind(query), args...)
+}
+
+func (db *sqlDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.DB.QueryRowContext(ctx, db.rebind(query), args...)
+}
+
+func (db *sqlDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sqlTx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlTx{Tx: tx, rebind: db.rebind}, nil
+}
+
+// sqlTx wraps sql.Tx to rewrite the placeholders of every query.
+type sqlTx struct {
+	*sql.Tx
+	rebind func(query string) string
+}
+
+func (tx *sqlTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, tx.rebind(query), args...)
+}
+
+func (tx *sqlTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, tx.rebind(query), args...)
+}
+
+func (tx *sqlTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, tx.rebind(query), args...)
+}
+
+// today returns the current UTC date as stored in the applied counts.
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// GetAppliedCountByCompany implements Datastore.
+func (d *sqlStore) GetAppliedCountByCompany(ctx context.Context, name string) (int, error) {
+	row := d.db.QueryRowContext(ctx, `
+		SELECT count
+		FROM applied_counts_by_company
+		WHERE name = ? AND date = ?
+	`, name, today())
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetAppliedTodayCount implements Datastore.
+// It returns the number of applications sent today on the platforms, or on
+// every platform when none is given.
+func (d *sqlStore) GetAppliedTodayCount(ctx context.Context, platforms ...string) (int, error) {
+	filter, args := platformFilter("platform", platforms)
+	row := d.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(count), 0) FROM applied_counts WHERE date = ?`+filter,
+		append([]interface{}{today()}, args...)...)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (d *sqlStore) IncAppliedTodayCount(ctx context.Context, platform string) error {
+	_, err := d.db.ExecContext(ctx, `
+		INSERT INTO applied_counts (platform, date, count)
+		VALUES (?, ?, 1)
+		ON CONFLICT (platform, date) DO UPDATE SET count = applied_counts.count + 1
+	`, platform, today())
+	return err
+}
+
+// GetAppliedTodayCountBySearch returns the number of applications sent today
+// for job postings of the platform found by the search.
+func (d *sqlStore) GetAppliedTodayCountBySearch(ctx context.Context, platform, search string) (int, error) {
+	midnight := time.Now().UTC().Truncate(24 * time.Hour)
+	row := d.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT h.platform || '/' || h.job_id)
+		FROM job_status_history h
+		JOIN job_postings p ON p.platform = h.platform AND p.id = h.job_id
+		WHERE p.platform = ? AND p.search = ? AND h.to_status = ? AND h.changed_at >= ?
+	`, platform, search, StatusSubmitted, midnight)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetUnappliedJobPosting returns a job posting that has not been applied to yet.
+// Only queued postings are considered. The best scoring and most recent one is picked.
+// Only job postings of the platforms are considered, or of every platform
+// when none is given. If there are no unapplied job postings, nil is returned.
+func (d *sqlStore) GetUnappliedJobPosting(ctx context.Context, platforms ...string) (*JobPosting, error) {
+	filter, args := platformFilter("platform", platforms)
+	row := d.db.QueryRowContext(ctx, `
+		SELECT `+jobPostingColumns+`
+		FROM job_postings
+		WHERE `+queuedJobPostings+filter+queueOrder+`
+		LIMIT 1
+	`, args...)
+
+	jobPosting, err := scanJobPosting(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return jobPosting, nil
+}
+
+// IncAppliedCountByCompany increases the applied count for a company.
+func (d *sqlStore) IncAppliedCountByCompany(ctx context.Context, name string) error {
+	_, err := d.db.ExecContext(ctx, `
+		INSERT INTO applied_counts_by_company (name, date, count)
+		VALUES (?, ?, 1)
+		ON CONFLICT (name, date) DO UPDATE SET count = applied_counts_by_company.count + 1
+	`, name, today())
+	return err
+}
+
+// InsertJobPosting stores a job posting and starts its status history.
+// Job postings without a status are queued. It returns ErrAlreadyExists
+// when the job posting is already stored.
+func (d *sqlStore) InsertJobPosting(ctx context.Context, jobPosting *JobPosting) error {
+	if jobPosting.Status == "" {
+		jobPosting.Status = StatusQueued
+	}
+
+	scoreDetails, err := json.Marshal(jobPosting.ScoreDetails)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO job_postings (`+jobPostingColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (platform, id) DO NOTHING
+	`,
+		jobPosting.Platform,
+		jobPosting.ID,
+		jobPosting.Url,
+		jobPosting.Title,
+		jobPosting.Company,
+		jobPosting.Status,
+		nullTime(jobPosting.PostedAt),
+		jobPosting.FilterReason,
+		jobPosting.Location,
+		jobPosting.Seniority,
+		jobPosting.Score,
+		string(scoreDetails),
+		jobPosting.WorkplaceType,
+		jobPosting.EmploymentType,
+		jobPosting.ApplicantCount,
+		jobPosting.Salary,
+		jobPosting.HiringTeam,
+		jobPosting.Description,
+		jobPosting.Search,
+		jobPosting.ApplyUrl,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n == 0 {
+		tx.Rollback()
+		return ErrAlreadyExists
+	}
+
+	// Every job posting is discovered first, then moves on to its status
+	if err := d.recordStatus(ctx, tx, jobPosting.Platform, jobPosting.ID, "", StatusDiscovered); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if jobPosting.Status != StatusDiscovered {
+		err := d.recordStatus(ctx, tx, jobPosting.Platform, jobPosting.ID, StatusDiscovered, jobPosting.Status)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetJobPostingStatus moves the job posting to the status and records the
+// change in its history. It returns ErrInvalidTransition when the current
+// status cannot move to the new one. Moving to applying claims the job:
+// only one caller succeeds, the others get ErrTaken.
+func (d *sqlStore) SetJobPostingStatus(ctx context.Context, platform, id, status string) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := d.setStatus(ctx, tx, platform, id, status); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setStatus moves the job posting to the status within the transaction.
+func (d *sqlStore) setStatus(ctx context.Context, tx *sqlTx, platform, id, status string) error {
+	var from string
+	row := tx.QueryRowContext(ctx, `SELECT status FROM job_postings WHERE platform = ? AND id = ?`, platform, id)
+	if err := row.Scan(&from); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if from == StatusApplying && status == StatusApplying {
+		return ErrTaken
+	}
+	if from == status {
+		return nil
+	}
+	if !CanTransition(from, status) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, from, status)
+	}
+
+	// Only update the status read above, bots sharing the database may
+	// have changed it since
+	res, err := tx.ExecContext(ctx,
+		`UPDATE job_postings SET status = ? WHERE platform = ? AND id = ? AND status = ?`,
+		status, platform, id, from,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if status == StatusApplying {
+			return ErrTaken
+		}
+		return fmt.Errorf("%w from %s to %s. status changed meanwhile", ErrInvalidTransition, from, status)
+	}
+
+	return d.recordStatus(ctx, tx, platform, id, from, status)
+}
+
+// recordStatus adds a status change to the history of the job posting.
+func (d *sqlStore) recordStatus(ctx context.Context, tx *sqlTx, platform, id, from, to string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO job_status_history (platform, job_id, from_status, to_status, changed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, platform, id, from, to, time.Now().UTC())
+	return err
+}
+
+// GetJobPostingHistory returns the status changes of the job posting, oldest first.
+func (d *sqlStore) GetJobPostingHistory(ctx context.Context, platform, id string) ([]*StatusChange, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT from_status, to_status, changed_at
+		FROM job_status_history
+		WHERE platform = ? AND job_id = ?
+		ORDER BY id
+	`, platform, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*StatusChange
+	for rows.Next() {
+		var change StatusChange
+		if err := rows.Scan(&change.From, &change.To, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, &change)
+	}
+
+	return history, rows.Err()
+}
+
+// GetStatusCounts returns the number of job postings currently in each status.
+func (d *sqlStore) GetStatusCounts(ctx context.Context) (map[string]int, error) {
+	return d.countByStatus(ctx, `SELECT status, COUNT(*) FROM job_postings GROUP BY status`)
+}
+
+// GetStatusFunnel returns the number of job postings that ever reached each status.
+func (d *sqlStore) GetStatusFunnel(ctx context.Context) (map[string]int, error) {
+	return d.countByStatus(ctx, `
+		SELECT to_status, COUNT(DISTINCT platform || '/' || job_id)
+		FROM job_status_history
+		GROUP BY to_status
+	`)
+}
+
+func (d *sqlStore) countByStatus(ctx context.Context, query string) (map[string]int, error) {
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetUnappliedJobPostings returns the queue of job postings in the order
+// GetUnappliedJobPosting picks them.
+func (d *sqlStore) GetUnappliedJobPostings(ctx context.Context) ([]*JobPosting, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT `+jobPostingColumns+`
+		FROM job_postings
+		WHERE `+queuedJobPostings+queueOrder)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobPostings []*JobPosting
+	for rows.Next() {
+		jobPosting, err := scanJobPosting(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobPostings = append(jobPostings, jobPosting)
+	}
+
+	return jobPostings, rows.Err()
+}
+
+// RequeueJobPostings puts the job postings with one of the statuses back
+// in the queue and returns how many were requeued.
+func (d *sqlStore) RequeueJobPostings(ctx context.Context, statuses ...string) (int, error) {
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	args := make([]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		if !CanTransition(status, StatusQueued) {
+			return 0, fmt.Errorf("%w from %s to %s", ErrInvalidTransition, status, StatusQueued)
+		}
+		args = append(args, status)
+	}
+
+	return d.requeue(ctx, `
+		SELECT platform, id
+		FROM job_postings
+		WHERE status IN (?`+strings.Repeat(", ?", len(statuses)-1)+`)
+	`, args...)
+}
+
+// RequeueStaleJobPostings puts the job postings that moved to the status
+// before the given time, and still have it, back in the queue and returns
+// how many were requeued.
+func (d *sqlStore) RequeueStaleJobPostings(ctx context.Context, status string, before time.Time) (int, error) {
+	if !CanTransition(status, StatusQueued) {
+		return 0, fmt.Errorf("%w from %s to %s", ErrInvalidTransition, status, StatusQueued)
+	}
+
+	return d.requeue(ctx, `
+		SELECT p.platform, p.id
+		FROM job_postings p
+		WHERE p.status = ? AND (
+			SELECT MAX(h.changed_at)
+			FROM job_status_history h
+			WHERE h.platform = p.platform AND h.job_id = p.id AND h.to_status = ?
+		) < ?
+	`, status, status, before.UTC())
+}
+
+// requeue puts the job postings selected by the query back in the queue.
+func (d *sqlStore) requeue(ctx context.Context, query string, args ...interface{}) (int, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var keys [][2]string
+	for rows.Next() {
+		var key [2]string
+		if err := rows.Scan(&key[0], &key[1]); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return 0, err
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	n := 0
+	for _, key := range keys {
+		err := d.setStatus(ctx, tx, key[0], key[1], StatusQueued)
+		// Another bot moved the job posting on since it was selected
+		if errors.Is(err, ErrInvalidTransition) {
+			continue
+		}
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		n++
+	}
+
+	return n, tx.Commit()
+}
+
+// GetJobPostings returns every stored job posting, best scoring first.
+func (d *sqlStore) GetJobPostings(ctx context.Context) ([]*JobPosting, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT `+jobPostingColumns+`
+		FROM job_postings
+		ORDER BY score DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobPostings []*JobPosting
+	for rows.Next() {
+		jobPosting, err := scanJobPosting(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobPostings = append(jobPostings, jobPosting)
+	}
+
+	return jobPostings, rows.Err()
+}
+
+// GetRetryableJobPostings returns the job postings waiting for answers
+// whose questions have all been answered since. Only job postings of the
+// platforms are considered, or of every platform when none is given.
+func (d *sqlStore) GetRetryableJobPostings(ctx context.Context, platforms ...string) ([]*JobPosting, error) {
+	filter, args := platformFilter("p.platform", platforms)
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT `+jobPostingColumns+`
+		FROM job_postings p
+		WHERE p.status = ?
+		AND NOT EXISTS (
+			SELECT 1
+			FROM job_questions jq
+			JOIN questions q ON q.id = jq.question_id
+			WHERE jq.platform = p.platform AND jq.job_id = p.id AND q.answered = 0
+		)`+filter, append([]interface{}{StatusNeedsAnswer}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobPostings []*JobPosting
+	for rows.Next() {
+		jobPosting, err := scanJobPosting(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobPostings = append(jobPostings, jobPosting)
+	}
+
+	return jobPostings, rows.Err()
+}
+
+// InsertQuestion stores an unanswered question and links it to the job posting
+// it was asked for. Questions are unique by label, so a question asked by
+// several job postings only has to be answered once.
+func (d *sqlStore) InsertQuestion(ctx context.Context, platform, jobID string, question *Question) error {
+	options, err := json.Marshal(question.Options)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO questions (label, kind, options) VALUES (?, ?, ?) ON CONFLICT (label) DO NOTHING`,
+		question.Label, question.Kind, string(options),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	row := tx.QueryRowContext(ctx, `SELECT id, answer, answered FROM questions WHERE label = ?`, question.Label)
+	if err := row.Scan(&question.ID, &question.Answer, &question.Answered); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO job_questions (platform, job_id, question_id) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+		platform, jobID, question.ID,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetQuestions returns every stored question, answered or not.
+func (d *sqlStore) GetQuestions(ctx context.Context) ([]*Question, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT id, label, kind, options, answer, answered
+		FROM questions
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*Question
+	for rows.Next() {
+		var question Question
+		var options string
+		if err := rows.Scan(
+			&question.ID,
+			&question.Label,
+			&question.Kind,
+			&options,
+			&question.Answer,
+			&question.Answered,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(options), &question.Options); err != nil {
+			return nil, err
+		}
+		questions = append(questions, &question)
+	}
+
+	return questions, rows.Err()
+}
+
+// AnswerQuestion stores the answer to a question.
+func (d *sqlStore) AnswerQuestion(ctx context.Context, id int64, answer string) error {
+	res, err := d.db.ExecContext(ctx,
+		`UPDATE questions SET answer = ?, answered = 1 WHERE id = ?`,
+		answer, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetCheckpoint returns the crawl checkpoint of the search url,
+// or ErrNotFound when there is none.
+func (d *sqlStore) GetCheckpoint(ctx context.Context, platform, url string) (*Checkpoint, error) {
+	row := d.db.QueryRowContext(ctx, `
+		SELECT platform, url, start, job_ids, updated_at
+		FROM search_checkpoints
+		WHERE platform = ? AND url = ?
+	`, platform, url)
+
+	var checkpoint Checkpoint
+	var jobIDs string
+	if err := row.Scan(
+		&checkpoint.Platform,
+		&checkpoint.Url,
+		&checkpoint.Start,
+		&jobIDs,
+		&checkpoint.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(jobIDs), &checkpoint.JobIDs); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint stores the crawl checkpoint of a search url,
+// replacing the previous one. UpdatedAt is set to the current time.
+func (d *sqlStore) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if checkpoint.JobIDs == nil {
+		checkpoint.JobIDs = []string{}
+	}
+	jobIDs, err := json.Marshal(checkpoint.JobIDs)
+	if err != nil {
+		return err
+	}
+	checkpoint.UpdatedAt = time.Now().UTC()
+
+	_, err = d.db.ExecContext(ctx, `
+		INSERT INTO search_checkpoints (platform, url, start, job_ids, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (platform, url) DO UPDATE SET
+			start = excluded.start,
+			job_ids = excluded.job_ids,
+			updated_at = excluded.updated_at
+	`, checkpoint.Platform, checkpoint.Url, checkpoint.Start, string(jobIDs), checkpoint.UpdatedAt)
+	return err
+}
+
+// DeleteCheckpoint removes the crawl checkpoint of a search url.
+func (d *sqlStore) DeleteCheckpoint(ctx context.Context, platform, url string) error {
+	_, err := d.db.ExecContext(ctx,
+		`DELETE FROM search_checkpoints WHERE platform = ? AND url = ?`,
+		platform, url,
+	)
+	return err
+}
+
+// DeleteCheckpoints removes every crawl checkpoint.
+func (d *sqlStore) DeleteCheckpoints(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, `DELETE FROM search_checkpoints`)
+	return err
+}
+
+// platformFilter returns the condition limiting a query to the platforms
+// and its arguments, or nothing when no platform is given.
+func platformFilter(column string, platforms []string) (string, []interface{}) {
+	if len(platforms) == 0 {
+		return "", nil
+	}
+
+	args := make([]interface{}, 0, len(platforms))
+	for _, p := range platforms {
+		args = append(args, p)
+	}
+
+	return ` AND ` + column + ` IN (?` + strings.Repeat(", ?", len(platforms)-1) + `)`, args
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanJobPosting reads a row selected with jobPostingColumns.
+func scanJobPosting(row scanner) (*JobPosting, error) {
+	var jobPosting JobPosting
+	var postedAt sql.NullTime
+	var scoreDetails string
+	if err := row.Scan(
+		&jobPosting.Platform,
+		&jobPosting.ID,
+		&jobPosting.Url,
+		&jobPosting.Title,
+		&jobPosting.Company,
+		&jobPosting.Status,
+		&postedAt,
+		&jobPosting.FilterReason,
+		&jobPosting.Location,
+		&jobPosting.Seniority,
+		&jobPosting.Score,
+		&scoreDetails,
+		&jobPosting.WorkplaceType,
+		&jobPosting.EmploymentType,
+		&jobPosting.ApplicantCount,
+		&jobPosting.Salary,
+		&jobPosting.HiringTeam,
+		&jobPosting.Description,
+		&jobPosting.Search,
+		&jobPosting.ApplyUrl,
+	); err != nil {
+		return nil, err
+	}
+	jobPosting.PostedAt = postedAt.Time
+
+	if err := json.Unmarshal([]byte(scoreDetails), &jobPosting.ScoreDetails); err != nil {
+		return nil, err
+	}
+
+	return &jobPosting, nil
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (d *sqlStore) Close() error {
+	return d.db.Close()
+}
@@ -27,6 +27,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -34,227 +36,130 @@ import (
 	"github.com/spf13/viper"
 )
 
-const (
-	createSqliteTableQuery = `
-		CREATE TABLE IF NOT EXISTS job_postings (
-			platform TEXT,
-			id TEXT,
-			url TEXT,
-			job_title TEXT,
-			company TEXT,
-			applied INTEGER,
-			PRIMARY KEY (platform, id)
-		);
-
-		CREATE TABLE IF NOT EXISTS applied_counts (
-			platform TEXT,
-			date TEXT,
-			count INTEGER,
-			PRIMARY KEY (platform, date)
-		);
-
-		CREATE TABLE IF NOT EXISTS applied_counts_by_company (
-			name TEXT,
-			date TEXT,
-			count INTEGER,
-			PRIMARY KEY (name, date)
-		);
-	`
-)
-
-var _ Datastore = (*sqlite)(nil)
-
-type sqlite struct {
-	db *sql.DB
-}
-
-// GetAppliedCountByCompany implements Datastore.
-func (d *sqlite) GetAppliedCountByCompany(ctx context.Context, name string) (int, error) {
-	row := d.db.QueryRowContext(ctx, `SELECT count FROM applied_counts_by_company WHERE name = ?`, name)
-	if row.Err() != nil {
-		if row.Err() == sql.ErrNoRows {
-			return 0, nil
-		}
-
-		return 0, row.Err()
-	}
-
-	var count int
-	if err := row.Scan(&count); err != nil {
-		return 0, err
-	}
-
-	return count, nil
-}
-
-// GetAppliedTodayCount implements Datastore.
-func (d *sqlite) GetAppliedTodayCount(ctx context.Context) (int, error) {
-	row := d.db.QueryRowContext(ctx, `SELECT count FROM applied_counts WHERE date = date('now')`)
-	if row.Err() != nil {
-		if row.Err() == sql.ErrNoRows {
-			return 0, nil
-		}
-
-		return 0, row.Err()
-	}
-
-	var count int
-	if err := row.Scan(&count); err != nil {
-		return 0, err
+const createSqliteVersionTableQuery = `
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		applied_at DATETIME
+	)
+`
+
+// sqliteSchema returns the schema of the sqlite database in dbFile.
+func sqliteSchema(dbFile string) schema {
+	return schema{
+		migrations:         sqliteMigrations,
+		createVersionTable: createSqliteVersionTableQuery,
+		rebind:             func(query string) string { return query },
+		backup: func(ctx context.Context, db *sql.DB, version int) error {
+			return backupSqlite(ctx, db, dbFile, version)
+		},
 	}
-
-	return count, nil
 }
 
-func (d *sqlite) IncAppliedTodayCount(ctx context.Context, platform string) error {
-	tx, err := d.db.Begin()
+// NewSqliteDatastore creates a new SQLite-based datastore and migrates its
+// schema to the latest version. An empty dbFile uses the database next to
+// the config file.
+func NewSqliteDatastore(dbFile string) (Datastore, error) {
+	dbFile, err := sqliteFile(dbFile)
 	if err != nil {
-		return err
-	}
-
-	query := "SELECT count FROM applied_counts WHERE platform = ? AND date = date('now')"
-	row := tx.QueryRow(query, platform)
-
-	var existingCount int
-	err = row.Scan(&existingCount)
-	if err != nil && err != sql.ErrNoRows {
-		return err
+		return nil, err
 	}
 
-	newCount := 1
-	if existingCount > 0 {
-		newCount += existingCount
+	db, err := openSqlite(dbFile)
+	if err != nil {
+		return nil, err
 	}
 
-	insertQuery := "INSERT OR REPLACE INTO applied_counts (platform, date, count) VALUES (?, date('now'), ?)"
-	_, err = tx.Exec(insertQuery, platform, newCount)
-	if err != nil {
-		tx.Rollback()
-		return err
+	s := sqliteSchema(dbFile)
+	if _, err := migrate(context.Background(), db, s); err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	return tx.Commit()
+	return &sqlStore{db: &sqlDB{DB: db, rebind: s.rebind}}, nil
 }
 
-// GetUnappliedJobPosting returns a job posting that has not been applied to yet.
-// If there are no unapplied job postings, nil is returned.
-func (d *sqlite) GetUnappliedJobPosting(ctx context.Context) (*JobPosting, error) {
-	row := d.db.QueryRowContext(ctx, `
-		SELECT platform, id, url, job_title, company, applied
-		FROM job_postings
-		WHERE applied = 0
-		LIMIT 1
-	`)
-	if row.Err() != nil {
-		if row.Err() == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, row.Err()
+// sqliteFile returns the database file to use, defaulting to the config
+// file name with a .db extension.
+func sqliteFile(dbFile string) (string, error) {
+	if dbFile != "" {
+		return dbFile, nil
 	}
 
-	var jobPosting JobPosting
-	if err := row.Scan(
-		&jobPosting.Platform,
-		&jobPosting.ID,
-		&jobPosting.Url,
-		&jobPosting.Title,
-		&jobPosting.Company,
-		&jobPosting.Applied,
-	); err != nil {
-		return nil, err
+	cfgFile := viper.ConfigFileUsed()
+	if cfgFile == "" {
+		return "", errors.New("config file not found")
 	}
+	dbFile = strings.TrimSuffix(cfgFile, filepath.Ext(cfgFile)) + ".db"
+	log.Info().Str("db_file", dbFile).Msg("using default db file")
 
-	return &jobPosting, nil
+	return dbFile, nil
 }
 
-// IncAppliedCountByCompany increases the applied count for a company.
-func (d *sqlite) IncAppliedCountByCompany(ctx context.Context, name string) error {
-	tx, err := d.db.Begin()
+func openSqlite(dbFile string) (*sql.DB, error) {
+	dbFile, err := sqliteFile(dbFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	query := "SELECT count FROM applied_counts_by_company WHERE name = ? AND date = date('now')"
-	row := tx.QueryRow(query, name)
+	// Transactions take the write lock when they begin, so bots sharing the
+	// file wait for each other instead of failing to claim the same job
+	return sql.Open("sqlite3", dbFile+"?_txlock=immediate&_busy_timeout=5000")
+}
 
-	var existingCount int
-	err = row.Scan(&existingCount)
-	if err != nil && err != sql.ErrNoRows {
+// backupSqlite copies the database file next to itself before migrating it,
+// unless the database is new.
+func backupSqlite(ctx context.Context, db *sql.DB, dbFile string, version int) error {
+	var tables int
+	row := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name != 'schema_version'`)
+	if err := row.Scan(&tables); err != nil {
 		return err
 	}
-
-	newCount := 1
-	if existingCount > 0 {
-		newCount += existingCount
+	if tables == 0 {
+		return nil
 	}
 
-	insertQuery := "INSERT OR REPLACE INTO applied_counts_by_company (name, date, count) VALUES (?, date('now'), ?)"
-	_, err = tx.Exec(insertQuery, name, newCount)
+	data, err := os.ReadFile(dbFile)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
-	return tx.Commit()
+	backupFile := fmt.Sprintf("%s.v%d.bak", dbFile, version)
+	log.Info().Str("backup_file", backupFile).Msg("Backing up database before migrating")
+	return os.WriteFile(backupFile, data, 0o600)
 }
 
-func (d *sqlite) InsertJobPosting(ctx context.Context, jobPosting *JobPosting) error {
-	tx, err := d.db.Begin()
-	if err != nil {
-		return err
-	}
-
-	stmt, err := tx.Prepare(`
-		INSERT INTO job_postings (platform, id, url, job_title, company, applied)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`)
+// SqliteMigrationStatus returns every migration of the sqlite database,
+// with AppliedAt set on the applied ones. An empty dbFile uses the database
+// next to the config file.
+func SqliteMigrationStatus(ctx context.Context, dbFile string) ([]Migration, error) {
+	dbFile, err := sqliteFile(dbFile)
 	if err != nil {
-		tx.Rollback()
-		return err
+		return nil, err
 	}
 
-	_, err = stmt.Exec(
-		jobPosting.Platform,
-		jobPosting.ID,
-		jobPosting.Url,
-		jobPosting.Title,
-		jobPosting.Company,
-		jobPosting.Applied,
-	)
+	db, err := openSqlite(dbFile)
 	if err != nil {
-		tx.Rollback()
-		return err
+		return nil, err
 	}
+	defer db.Close()
 
-	return tx.Commit()
+	return migrationStatus(ctx, db, sqliteSchema(dbFile))
 }
 
-func (d *sqlite) Close() error {
-	return d.db.Close()
-}
-
-// NewDatastore creates a new SQLite-based datastore.
-func NewSqliteDatastore(dbFile string) (Datastore, error) {
-	if dbFile == "" {
-		cfgFile := viper.ConfigFileUsed()
-		if cfgFile == "" {
-			return nil, errors.New("config file not found")
-		}
-		dbFile = strings.TrimSuffix(cfgFile, filepath.Ext(cfgFile)) + ".db"
-		log.Info().Str("db_file", dbFile).Msg("using default db file")
-	}
-
-	db, err := sql.Open("sqlite3", dbFile)
+// MigrateSqlite runs the pending migrations of the sqlite database and
+// returns them. An empty dbFile uses the database next to the config file.
+func MigrateSqlite(ctx context.Context, dbFile string) ([]Migration, error) {
+	dbFile, err := sqliteFile(dbFile)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create the table if it doesn't exist
-	_, err = db.Exec(createSqliteTableQuery)
+	db, err := openSqlite(dbFile)
 	if err != nil {
-		db.Close()
 		return nil, err
 	}
+	defer db.Close()
 
-	return &sqlite{db: db}, nil
+	return migrate(ctx, db, sqliteSchema(dbFile))
 }
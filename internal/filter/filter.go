@@ -0,0 +1,314 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package filter evaluates boolean filter expressions against job postings.
+//
+// An expression compares the fields of a posting using == != < <= > >=,
+// matches them against regex patterns with =~ and !~, checks membership
+// with in, and combines the results with && || ! and parentheses:
+//
+//	title =~ "(?i)golang" && !(description =~ "(?i)clearance") && lang in ["english", "german"]
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Posting is the view of a job posting that expressions are evaluated against.
+type Posting struct {
+	Title       string
+	Company     string
+	Description string
+	Location    string
+	Seniority   string
+	Lang        string
+	Score       float64
+	Workplace   string
+	Employment  string
+	Applicants  float64
+	Salary      float64
+}
+
+// fields maps the names usable in expressions to the posting values.
+var fields = map[string]func(p *Posting) interface{}{
+	"title":       func(p *Posting) interface{} { return p.Title },
+	"company":     func(p *Posting) interface{} { return p.Company },
+	"description": func(p *Posting) interface{} { return p.Description },
+	"location":    func(p *Posting) interface{} { return p.Location },
+	"seniority":   func(p *Posting) interface{} { return p.Seniority },
+	"lang":        func(p *Posting) interface{} { return p.Lang },
+	"score":       func(p *Posting) interface{} { return p.Score },
+	"workplace":   func(p *Posting) interface{} { return p.Workplace },
+	"employment":  func(p *Posting) interface{} { return p.Employment },
+	"applicants":  func(p *Posting) interface{} { return p.Applicants },
+	"salary":      func(p *Posting) interface{} { return p.Salary },
+}
+
+func isField(name string) bool {
+	_, ok := fields[name]
+	return ok
+}
+
+// Fields returns the names of the fields usable in expressions.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expr is a compiled filter expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses the expression.
+func Compile(src string) (*Expr, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q. %w", src, err)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// Eval evaluates the expression, which must result in a boolean.
+func (e *Expr) Eval(p *Posting) (bool, error) {
+	v, err := e.root.eval(p)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate filter %q. %w", e.src, err)
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter %q does not result in a boolean", e.src)
+	}
+
+	return b, nil
+}
+
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Filter is a list of expressions that must all hold for a posting to pass.
+type Filter struct {
+	exprs []*Expr
+}
+
+// New compiles the expressions into a Filter.
+func New(exprs []string) (*Filter, error) {
+	f := &Filter{exprs: make([]*Expr, 0, len(exprs))}
+	for _, src := range exprs {
+		e, err := Compile(src)
+		if err != nil {
+			return nil, err
+		}
+		f.exprs = append(f.exprs, e)
+	}
+
+	return f, nil
+}
+
+// Match returns the first expression the posting does not satisfy,
+// or nil when it satisfies all of them.
+func (f *Filter) Match(p *Posting) (*Expr, error) {
+	for _, e := range f.exprs {
+		ok, err := e.Eval(p)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return e, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (n *literal) eval(_ *Posting) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *field) eval(p *Posting) (interface{}, error) {
+	return fields[n.name](p), nil
+}
+
+func (n *list) eval(p *Posting) (interface{}, error) {
+	items := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(p)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func (n *not) eval(p *Posting) (interface{}, error) {
+	b, err := evalBool(n.x, p)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+func (n *logical) eval(p *Posting) (interface{}, error) {
+	x, err := evalBool(n.x, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit like Go does
+	if n.op == "&&" && !x || n.op == "||" && x {
+		return x, nil
+	}
+
+	return evalBool(n.y, p)
+}
+
+func (n *match) eval(p *Posting) (interface{}, error) {
+	v, err := n.x.eval(p)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot match %v against a pattern", v)
+	}
+
+	return n.re.MatchString(s) != n.negate, nil
+}
+
+func (n *compare) eval(p *Posting) (interface{}, error) {
+	x, err := n.x.eval(p)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := n.y.eval(p)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "in":
+		items, ok := y.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("right side of in must be a list")
+		}
+		for _, item := range items {
+			if equal(x, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case "==":
+		return equal(x, y), nil
+
+	case "!=":
+		return !equal(x, y), nil
+	}
+
+	// Ordering works on numbers and strings of the same type
+	switch x := x.(type) {
+	case float64:
+		y, ok := y.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %v with %v", x, y)
+		}
+		return order(n.op, compareNumbers(x, y)), nil
+
+	case string:
+		y, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %q with %v", x, y)
+		}
+		return order(n.op, strings.Compare(x, y)), nil
+	}
+
+	return nil, fmt.Errorf("cannot compare %v with %v", x, y)
+}
+
+func evalBool(n node, p *Posting) (bool, error) {
+	v, err := n.eval(p)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%v is not a boolean", v)
+	}
+	return b, nil
+}
+
+// equal compares strings case-insensitively and other values exactly.
+func equal(x, y interface{}) bool {
+	xs, xok := x.(string)
+	ys, yok := y.(string)
+	if xok && yok {
+		return strings.EqualFold(xs, ys)
+	}
+
+	// Lists are not comparable
+	if _, ok := x.([]interface{}); ok {
+		return false
+	}
+	if _, ok := y.([]interface{}); ok {
+		return false
+	}
+	return x == y
+}
+
+func compareNumbers(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func order(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
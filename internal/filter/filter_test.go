@@ -0,0 +1,135 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/filter"
+)
+
+func TestEval(t *testing.T) {
+	posting := &filter.Posting{
+		Title:       "Senior Golang Engineer",
+		Company:     "Acme",
+		Description: "Build services in Go on Kubernetes",
+		Location:    "Berlin, Germany (Remote)",
+		Seniority:   "Mid-Senior level",
+		Lang:        "english",
+		Score:       7,
+	}
+
+	tests := map[string]bool{
+		`title =~ "(?i)golang"`:                            true,
+		`title !~ "(?i)golang"`:                            false,
+		`company == "acme"`:                                true,
+		`company != "Acme"`:                                false,
+		`lang in ["english", "german"]`:                    true,
+		`lang in ["german"]`:                               false,
+		`score >= 5 && score < 10`:                         true,
+		`score > 7`:                                        false,
+		`!(description =~ "(?i)clearance")`:                true,
+		`title =~ "Junior" || location =~ "Remote"`:        true,
+		`!(title =~ "(?i)senior") || location =~ "Remote"`: true,
+		`!(title =~ "(?i)senior") || location =~ "Munich"`: false,
+		"title =~ `\\bGolang\\b` && (true || false)":       true,
+		`title =~ "(?i)golang" && !(description =~ "(?i)clearance") && lang in ["english","german"]`: true,
+	}
+
+	for src, want := range tests {
+		expr, err := filter.Compile(src)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", src, err)
+		}
+
+		got, err := expr.Eval(posting)
+		if err != nil {
+			t.Fatalf("failed to evaluate %q: %v", src, err)
+		}
+		if got != want {
+			t.Fatalf("%q = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, src := range []string{
+		`benefits > 10`,
+		`title =~ company`,
+		`title =~ "("`,
+		`(title == "x"`,
+		`title == "x" extra`,
+		`lang in ["english"`,
+		`title == "unterminated`,
+		`title @ "x"`,
+	} {
+		if _, err := filter.Compile(src); err == nil {
+			t.Fatalf("expected %q to fail to compile", src)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for _, src := range []string{
+		`title`,
+		`score =~ "1"`,
+		`title > 1`,
+		`lang in "english"`,
+		`!title`,
+	} {
+		expr, err := filter.Compile(src)
+		if err != nil {
+			t.Fatalf("failed to compile %q: %v", src, err)
+		}
+		if _, err := expr.Eval(&filter.Posting{}); err == nil {
+			t.Fatalf("expected %q to fail to evaluate", src)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	f, err := filter.New([]string{
+		`lang == "english"`,
+		`!(seniority =~ "(?i)senior") || location =~ "(?i)remote"`,
+	})
+	if err != nil {
+		t.Fatalf("failed to create filter: %v", err)
+	}
+
+	failed, err := f.Match(&filter.Posting{Lang: "english", Seniority: "Mid-Senior level", Location: "Berlin"})
+	if err != nil {
+		t.Fatalf("failed to match: %v", err)
+	}
+	if failed == nil || failed.String() != `!(seniority =~ "(?i)senior") || location =~ "(?i)remote"` {
+		t.Fatalf("expected the seniority rule to fail, got %v", failed)
+	}
+
+	failed, err = f.Match(&filter.Posting{Lang: "english", Seniority: "Mid-Senior level", Location: "Remote"})
+	if err != nil {
+		t.Fatalf("failed to match: %v", err)
+	}
+	if failed != nil {
+		t.Fatalf("expected the posting to pass, got %v", failed)
+	}
+}
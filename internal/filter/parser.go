@@ -0,0 +1,254 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// node is an expression that evaluates to a value against a posting.
+type node interface {
+	eval(p *Posting) (interface{}, error)
+}
+
+type literal struct{ value interface{} }
+
+type field struct{ name string }
+
+type list struct{ items []node }
+
+type not struct{ x node }
+
+type logical struct {
+	op   string
+	x, y node
+}
+
+type compare struct {
+	op   string
+	x, y node
+}
+
+type match struct {
+	negate bool
+	x      node
+	re     *regexp.Regexp
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return p.errorf(t, "expected %s", what)
+	}
+	return nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf(format+" at end of expression", args...)
+	}
+	return fmt.Errorf(format+" at %d, got %q", append(args, t.pos, t.text)...)
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected token")
+	}
+
+	return n, nil
+}
+
+// or := and ('||' and)*
+func (p *parser) or() (node, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOp && p.peek().text == "||" {
+		p.next()
+		y, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		x = &logical{op: "||", x: x, y: y}
+	}
+
+	return x, nil
+}
+
+// and := unary ('&&' unary)*
+func (p *parser) and() (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOp && p.peek().text == "&&" {
+		p.next()
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = &logical{op: "&&", x: x, y: y}
+	}
+
+	return x, nil
+}
+
+// unary := '!' unary | comparison
+func (p *parser) unary() (node, error) {
+	if t := p.peek(); t.kind == tokenOp && t.text == "!" {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &not{x: x}, nil
+	}
+
+	return p.comparison()
+}
+
+// comparison := primary (op primary)?
+func (p *parser) comparison() (node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokenOp && (t.text == "=~" || t.text == "!~"):
+		p.next()
+		pattern := p.next()
+		if pattern.kind != tokenString {
+			return nil, p.errorf(pattern, "expected a string pattern")
+		}
+
+		re, err := regexp.Compile(pattern.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern at %d. %w", pattern.pos, err)
+		}
+		return &match{negate: t.text == "!~", x: x, re: re}, nil
+
+	case t.kind == tokenOp && t.text != "&&" && t.text != "||" && t.text != "!",
+		t.kind == tokenIdent && t.text == "in":
+		p.next()
+		y, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return &compare{op: t.text, x: x, y: y}, nil
+	}
+
+	return x, nil
+}
+
+// primary := '(' or ')' | '[' items ']' | string | number | true | false | field
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return x, nil
+
+	case tokenLBracket:
+		l := &list{}
+		for p.peek().kind != tokenRBracket {
+			if len(l.items) > 0 {
+				if err := p.expect(tokenComma, "','"); err != nil {
+					return nil, err
+				}
+			}
+
+			item, err := p.primary()
+			if err != nil {
+				return nil, err
+			}
+			l.items = append(l.items, item)
+		}
+		p.next()
+		return l, nil
+
+	case tokenString:
+		return &literal{value: t.value}, nil
+
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number")
+		}
+		return &literal{value: n}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		}
+
+		if !isField(t.text) {
+			return nil, p.errorf(t, "unknown field")
+		}
+		return &field{name: t.text}, nil
+	}
+
+	return nil, p.errorf(t, "expected a value")
+}
@@ -0,0 +1,292 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/scoring"
+	"github.com/k1ng440/job-bot/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+type regex struct {
+	title       []*regexp.Regexp
+	company     []*regexp.Regexp
+	description []*regexp.Regexp
+}
+
+// PostingFilter decides which job postings to apply to. It needs no browser,
+// so the filters can be tried out against stored or saved postings.
+type PostingFilter struct {
+	config    config.PostingFilters
+	whitelist *regex
+	blacklist *regex
+	scorer    *scoring.Scorer
+	exprs     *Filter
+}
+
+// NewPostingFilter compiles the configured posting filters.
+func NewPostingFilter(cfg config.PostingFilters) (*PostingFilter, error) {
+	scorer, err := scoring.New(cfg.Scoring)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs, err := New(cfg.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	whitelist, err := compileRegex(cfg.Whitelists)
+	if err != nil {
+		return nil, fmt.Errorf("invalid whitelist. %w", err)
+	}
+
+	blacklist, err := compileRegex(cfg.Blacklists)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blacklist. %w", err)
+	}
+
+	switch cfg.Salary.Missing {
+	case "", config.SalaryMissingAllow, config.SalaryMissingReject:
+	default:
+		return nil, fmt.Errorf("invalid salary missing policy %q, expected %s or %s",
+			cfg.Salary.Missing, config.SalaryMissingAllow, config.SalaryMissingReject)
+	}
+
+	// Default to english
+	if len(cfg.Languages) == 0 {
+		cfg.Languages = []string{"english"}
+	}
+
+	return &PostingFilter{
+		config:    cfg,
+		whitelist: whitelist,
+		blacklist: blacklist,
+		scorer:    scorer,
+		exprs:     exprs,
+	}, nil
+}
+
+// compileRegex compiles the configured patterns.
+func compileRegex(cfg config.Patterns) (*regex, error) {
+	r := &regex{}
+	for _, c := range []struct {
+		patterns []string
+		compiled *[]*regexp.Regexp
+	}{
+		{cfg.Title, &r.title},
+		{cfg.Company, &r.company},
+		{cfg.Description, &r.description},
+	} {
+		*c.compiled = []*regexp.Regexp{}
+		for _, p := range c.patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q. %w", p, err)
+			}
+			*c.compiled = append(*c.compiled, re)
+		}
+	}
+
+	return r, nil
+}
+
+// Result tells whether a posting passed the filters and, if not,
+// which rule rejected it.
+type Result struct {
+	Allowed bool
+	// Rule is the rejecting rule: age, title_whitelist, company_whitelist,
+	// description_whitelist, title, company, description, salary, language,
+	// filter or score
+	Rule string
+	// Match is the pattern that matched, or the detected language
+	Match string
+}
+
+// Reason describes the rejecting rule, or is empty for allowed postings.
+func (r Result) Reason() string {
+	if r.Allowed {
+		return ""
+	}
+	return r.Rule + ": " + r.Match
+}
+
+func reject(rule, match string) Result {
+	return Result{Rule: rule, Match: match}
+}
+
+// matchAny returns the first pattern matching s.
+func matchAny(patterns []*regexp.Regexp, s string) (*regexp.Regexp, bool) {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// annualSalary returns the top of the posting's salary range per year,
+// or 0 when it shows no salary in the configured currency.
+func (f *PostingFilter) annualSalary(post *datastore.JobPosting) float64 {
+	salary, ok := utils.ParseSalary(post.Salary)
+	if !ok {
+		return 0
+	}
+
+	// Salaries without a currency are assumed to be in the configured one
+	if f.config.Salary.Currency != "" && salary.Currency != "" &&
+		!strings.EqualFold(salary.Currency, f.config.Salary.Currency) {
+		return 0
+	}
+
+	_, max := salary.Annual()
+	return max
+}
+
+// Evaluate runs the posting through the filters. Postings getting past the
+// regex and language filters have their score set.
+func (f *PostingFilter) Evaluate(post *datastore.JobPosting) Result {
+	return f.evaluate(post, true)
+}
+
+// EvaluateWithoutDescription runs the posting through the filters, skipping
+// the description and language rules. It is meant for postings stored
+// before descriptions were kept, which every such rule would reject.
+func (f *PostingFilter) EvaluateWithoutDescription(post *datastore.JobPosting) Result {
+	return f.evaluate(post, false)
+}
+
+func (f *PostingFilter) evaluate(post *datastore.JobPosting, withDescription bool) Result {
+	description := post.Description
+
+	// Skip postings older than MaxAgeDays when the posted date is known
+	if f.config.MaxAgeDays > 0 && !post.PostedAt.IsZero() &&
+		time.Since(post.PostedAt) > time.Duration(f.config.MaxAgeDays)*24*time.Hour {
+		return reject("age", post.PostedAt.Format(time.RFC3339))
+	}
+
+	// Every non-empty whitelist needs a match
+	if _, ok := matchAny(f.whitelist.title, post.Title); !ok && len(f.whitelist.title) > 0 {
+		return reject("title_whitelist", "no match")
+	}
+
+	if _, ok := matchAny(f.whitelist.company, post.Company); !ok && len(f.whitelist.company) > 0 {
+		return reject("company_whitelist", "no match")
+	}
+
+	if _, ok := matchAny(f.whitelist.description, description); withDescription && !ok && len(f.whitelist.description) > 0 {
+		return reject("description_whitelist", "no match")
+	}
+
+	if re, ok := matchAny(f.blacklist.title, post.Title); ok {
+		return reject("title", re.String())
+	}
+
+	if re, ok := matchAny(f.blacklist.company, post.Company); ok {
+		return reject("company", re.String())
+	}
+
+	if re, ok := matchAny(f.blacklist.description, description); withDescription && ok {
+		return reject("description", re.String())
+	}
+
+	salary := f.annualSalary(post)
+	if f.config.Salary.MinAnnual > 0 {
+		if salary == 0 && f.config.Salary.Missing == config.SalaryMissingReject {
+			return reject("salary", "not shown")
+		}
+		if salary > 0 && salary < f.config.Salary.MinAnnual {
+			return reject("salary", fmt.Sprintf("%g < %g", salary, f.config.Salary.MinAnnual))
+		}
+	}
+
+	var lang string
+	if withDescription {
+		// detect language
+		lang = utils.DetectLanguage(description)
+		if lang == "" {
+			log.Warn().Msg("Failed to detect language")
+		}
+
+		// Check if the language is allowed
+		allowedLang := false
+		for _, allowed := range f.config.Languages {
+			if lang == strings.ToLower(allowed) {
+				allowedLang = true
+				break
+			}
+		}
+		if !allowedLang {
+			return reject("language", lang)
+		}
+	}
+
+	res := f.scorer.Score(scoring.Job{
+		Title:       post.Title,
+		Company:     post.Company,
+		Description: description,
+		Location:    post.Location,
+		Seniority:   post.Seniority,
+	})
+
+	post.Score = res.Score
+	post.ScoreDetails = make([]datastore.ScoreContribution, 0, len(res.Contributions))
+	for _, c := range res.Contributions {
+		post.ScoreDetails = append(post.ScoreDetails, datastore.ScoreContribution{Rule: c.Rule, Weight: c.Weight})
+	}
+
+	failed, err := f.exprs.Match(&Posting{
+		Title:       post.Title,
+		Company:     post.Company,
+		Description: description,
+		Location:    post.Location,
+		Seniority:   post.Seniority,
+		Lang:        lang,
+		Score:       res.Score,
+		Workplace:   post.WorkplaceType,
+		Employment:  post.EmploymentType,
+		Applicants:  float64(post.ApplicantCount),
+		Salary:      salary,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("title", post.Title).Msg("Failed to evaluate filter")
+		return reject("filter", err.Error())
+	}
+	if failed != nil {
+		return reject("filter", failed.String())
+	}
+
+	if !f.scorer.Accept(res) {
+		return reject("score", fmt.Sprintf("%g < %g", res.Score, f.scorer.MinScore()))
+	}
+
+	return Result{Allowed: true}
+}
@@ -0,0 +1,107 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/filter"
+)
+
+func TestEvaluateLists(t *testing.T) {
+	description := "We are looking for an engineer to build our Go services and keep them running in production."
+
+	f, err := filter.NewPostingFilter(config.PostingFilters{
+		Whitelists: config.Patterns{
+			Title:       []string{`(?i)golang`, `(?i)\bgo\b`},
+			Description: []string{`(?i)production`},
+		},
+		Blacklists: config.Patterns{
+			Title:       []string{`(?i)senior`},
+			Company:     []string{`(?i)acme`},
+			Description: []string{`(?i)on-call`},
+		},
+		// Rejects what gets past the lists before the slow language detection
+		Salary: config.Salary{MinAnnual: 1, Missing: config.SalaryMissingReject},
+	})
+	if err != nil {
+		t.Fatalf("failed to create posting filter: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		post datastore.JobPosting
+		rule string
+	}{
+		{"every whitelist matches", datastore.JobPosting{Title: "Golang Engineer", Company: "Other", Description: description}, "salary"},
+		{"any pattern of a whitelist matches", datastore.JobPosting{Title: "Go Developer", Company: "Other", Description: description}, "salary"},
+		{"empty company whitelist does not restrict", datastore.JobPosting{Title: "Go Developer", Company: "Anyone", Description: description}, "salary"},
+		{"title whitelist", datastore.JobPosting{Title: "Java Engineer", Company: "Other", Description: description}, "title_whitelist"},
+		{"description whitelist", datastore.JobPosting{Title: "Golang Engineer", Company: "Other", Description: "We are looking for an engineer to build our Go services."}, "description_whitelist"},
+		{"whitelist before blacklist", datastore.JobPosting{Title: "Senior Java Engineer", Company: "Acme", Description: description}, "title_whitelist"},
+		{"title blacklist", datastore.JobPosting{Title: "Senior Golang Engineer", Company: "Other", Description: description}, "title"},
+		{"company blacklist", datastore.JobPosting{Title: "Golang Engineer", Company: "Acme", Description: description}, "company"},
+		{"description blacklist", datastore.JobPosting{Title: "Golang Engineer", Company: "Other", Description: description + " You will be on-call."}, "description"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := f.Evaluate(&tc.post); res.Allowed || res.Rule != tc.rule {
+				t.Fatalf("expected the posting to be rejected by %s, got %+v", tc.rule, res)
+			}
+		})
+	}
+
+	f, err = filter.NewPostingFilter(config.PostingFilters{Whitelists: config.Patterns{Company: []string{`(?i)acme`}}})
+	if err != nil {
+		t.Fatalf("failed to create posting filter: %v", err)
+	}
+	if res := f.Evaluate(&datastore.JobPosting{Title: "Golang Engineer", Company: "Other", Description: description}); res.Rule != "company_whitelist" {
+		t.Fatalf("expected the company whitelist to reject the posting, got %+v", res)
+	}
+}
+
+func TestEvaluateWithoutDescription(t *testing.T) {
+	f, err := filter.NewPostingFilter(config.PostingFilters{
+		Languages:  []string{"german"},
+		Whitelists: config.Patterns{Description: []string{`(?i)golang`}},
+		Blacklists: config.Patterns{Title: []string{`(?i)java`}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create posting filter: %v", err)
+	}
+
+	post := &datastore.JobPosting{Title: "Golang Engineer", Company: "Acme"}
+	if res := f.Evaluate(post); res.Rule != "description_whitelist" {
+		t.Fatalf("expected a posting without description to fail the description whitelist, got %+v", res)
+	}
+	if res := f.EvaluateWithoutDescription(post); !res.Allowed {
+		t.Fatalf("expected the description and language rules to be skipped, got %+v", res)
+	}
+
+	post.Title = "Java Engineer"
+	if res := f.EvaluateWithoutDescription(post); res.Rule != "title" {
+		t.Fatalf("expected the title blacklist to still apply, got %+v", res)
+	}
+}
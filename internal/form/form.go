@@ -0,0 +1,425 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package form fills the questions of application forms from an answer bank.
+package form
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pcdp "github.com/chromedp/cdproto/cdp"
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+)
+
+// Kind is the type of an input found in a form.
+type Kind string
+
+const (
+	Text     Kind = "text"
+	Number   Kind = "number"
+	Textarea Kind = "textarea"
+	Select   Kind = "select"
+	Radio    Kind = "radio"
+	Checkbox Kind = "checkbox"
+	File     Kind = "file"
+)
+
+// Option is a choice of a select, radio group or checkbox.
+// Selector points at the element to click, which is empty for select options.
+type Option struct {
+	Label    string `json:"label"`
+	Selector string `json:"selector"`
+}
+
+// Field is a question of a form as collected by collectFieldsJS.
+type Field struct {
+	Selector string   `json:"selector"`
+	Kind     Kind     `json:"kind"`
+	Label    string   `json:"label"`
+	Value    string   `json:"value"`
+	Required bool     `json:"required"`
+	Options  []Option `json:"options"`
+}
+
+func (f *Field) OptionLabels() []string {
+	labels := make([]string, 0, len(f.Options))
+	for _, o := range f.Options {
+		labels = append(labels, o.Label)
+	}
+	return labels
+}
+
+func (f *Field) Option(label string) *Option {
+	for i := range f.Options {
+		if f.Options[i].Label == label {
+			return &f.Options[i]
+		}
+	}
+	return nil
+}
+
+// collectFieldsJS tags every question inside the root element with a
+// data-jb-field attribute and returns them as a list of Field. Radio and
+// checkbox groups are read from the group elements, labelled by their
+// groupLabel element.
+const collectFieldsJS = `((root, group, groupLabel) => {
+	const form = document.querySelector(root);
+	if (!form) return [];
+
+	window.__jbField = window.__jbField || 0;
+	const mark = (el) => {
+		if (!el.dataset.jbField) el.dataset.jbField = String(window.__jbField++);
+		return '[data-jb-field="' + el.dataset.jbField + '"]';
+	};
+	const text = (el) => el ? el.innerText.split('\n')[0].trim() : '';
+	const labelOf = (el) => {
+		if (el.id) {
+			const label = form.querySelector('label[for="' + CSS.escape(el.id) + '"]');
+			if (label) return label;
+		}
+		return el.closest('label');
+	};
+	const labelText = (el) => text(labelOf(el)) || (el.getAttribute('aria-label') || '').trim();
+	const required = (el) => el.required || el.getAttribute('aria-required') === 'true';
+	const placeholder = (o) => o.value === '' || /^select an option$/i.test(o.text.trim());
+
+	const fields = [];
+	form.querySelectorAll(group).forEach((fs) => {
+		const radios = fs.querySelectorAll('input[type=radio]');
+		const inputs = radios.length ? radios : fs.querySelectorAll('input[type=checkbox]');
+		if (!inputs.length) return;
+
+		fields.push({
+			selector: mark(fs),
+			kind: radios.length ? 'radio' : 'checkbox',
+			label: text(fs.querySelector(groupLabel)),
+			value: Array.from(inputs).filter((i) => i.checked).map(labelText).join(', '),
+			required: required(fs) || Array.from(inputs).some(required),
+			options: Array.from(inputs).map((i) => ({label: labelText(i), selector: mark(labelOf(i) || i)})),
+		});
+	});
+
+	form.querySelectorAll('input, select, textarea').forEach((el) => {
+		const type = (el.getAttribute('type') || '').toLowerCase();
+		if (type === 'hidden' || type === 'submit' || type === 'button') return;
+		if ((type === 'radio' || type === 'checkbox') && el.closest(group)) return;
+
+		const field = {selector: mark(el), label: labelText(el), value: el.value, required: required(el), options: []};
+		if (el.tagName === 'SELECT') {
+			const selected = el.options[el.selectedIndex];
+			field.kind = 'select';
+			field.value = selected && !placeholder(selected) ? selected.text.trim() : '';
+			field.options = Array.from(el.options).filter((o) => !placeholder(o)).map((o) => ({label: o.text.trim(), selector: ''}));
+		} else if (el.tagName === 'TEXTAREA') {
+			field.kind = 'textarea';
+		} else if (type === 'checkbox') {
+			field.kind = 'checkbox';
+			field.value = el.checked ? labelText(el) : '';
+			field.options = [{label: labelText(el), selector: mark(labelOf(el) || el)}];
+		} else if (type === 'radio') {
+			return;
+		} else if (type === 'file') {
+			field.kind = 'file';
+		} else if (type === 'number' || el.id.endsWith('-numeric')) {
+			field.kind = 'number';
+		} else {
+			field.kind = 'text';
+		}
+		fields.push(field);
+	});
+
+	return fields;
+})`
+
+// selectOptionJS picks the option with the given text and notifies the page.
+const selectOptionJS = `((selector, label) => {
+	const el = document.querySelector(selector);
+	const option = Array.from(el.options).find((o) => o.text.trim() === label);
+	if (!option) return false;
+	el.value = option.value;
+	el.dispatchEvent(new Event('change', {bubbles: true}));
+	return true;
+})`
+
+var coverLetterLabel = regexp.MustCompile(`(?i)cover\s*letter`)
+
+// Filler fills the questions of a form from an answer bank.
+type Filler struct {
+	Bank *answers.Bank
+
+	// Text answers the text questions the bank has no answer for, e.g. with a cover letter
+	Text func(f *Field) (string, bool)
+
+	// Suggestions is the selector of the suggestions some text fields show
+	// while typing. The first suggestion is picked, as location and similar
+	// fields only accept a suggested value
+	Suggestions string
+
+	// Group is the selector of the elements holding a radio or checkbox group
+	// and its question, and GroupLabel the selector of the question inside it.
+	// They default to fieldset and legend
+	Group      string
+	GroupLabel string
+}
+
+// collect returns the questions inside the element matching root.
+func (fl *Filler) collect(ctx context.Context, root string) ([]Field, error) {
+	group, groupLabel := fl.Group, fl.GroupLabel
+	if group == "" {
+		group = "fieldset"
+	}
+	if groupLabel == "" {
+		groupLabel = "legend"
+	}
+
+	var fields []Field
+	if err := cdp.Run(ctx, cdp.Evaluate(CallJS(collectFieldsJS, root, group, groupLabel), &fields)); err != nil {
+		return nil, fmt.Errorf("failed to collect form fields. %w", err)
+	}
+
+	return fields, nil
+}
+
+// Fill fills every empty question inside the element matching root.
+// Required questions without a usable answer are returned so the caller can
+// decide what to do with them.
+func (fl *Filler) Fill(ctx context.Context, root string) ([]Field, error) {
+	fields, err := fl.collect(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var unanswered []Field
+	for _, f := range fields {
+		if f.Kind == File || f.Value != "" {
+			continue
+		}
+
+		ok, err := fl.FillField(ctx, &f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fill %q. %w", f.Label, err)
+		}
+
+		if !ok && f.Required {
+			log.Debug().Str("label", f.Label).Str("kind", string(f.Kind)).Msg("No answer for question")
+			unanswered = append(unanswered, f)
+		}
+	}
+
+	return unanswered, nil
+}
+
+// FillField fills a single question and reports whether an answer was found.
+func (fl *Filler) FillField(ctx context.Context, f *Field) (bool, error) {
+	a, ok := fl.answer(f)
+	if !ok {
+		return false, nil
+	}
+
+	switch f.Kind {
+	case Text, Textarea, Number:
+		if err := cdp.Run(ctx,
+			cdp.Clear(f.Selector, cdp.ByQuery),
+			cdp.SendKeys(f.Selector, a.text, cdp.ByQuery),
+		); err != nil {
+			return false, err
+		}
+
+		if fl.Suggestions != "" {
+			suggestions, err := Exists(ctx, fl.Suggestions)
+			if err != nil {
+				return false, err
+			}
+			if suggestions {
+				if err := cdp.Run(ctx, cdp.Click(fl.Suggestions, cdp.ByQuery)); err != nil {
+					return false, err
+				}
+			}
+		}
+
+	case Select:
+		var selected bool
+		if err := cdp.Run(ctx, cdp.Evaluate(CallJS(selectOptionJS, f.Selector, a.text), &selected)); err != nil {
+			return false, err
+		}
+		if !selected {
+			return false, nil
+		}
+
+	default:
+		for _, sel := range a.clicks {
+			if err := cdp.Run(ctx, cdp.Click(sel, cdp.ByQuery)); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	log.Debug().Str("label", f.Label).Str("kind", string(f.Kind)).Msg("Question answered")
+	return true, nil
+}
+
+// answer is how a question is answered.
+type answer struct {
+	// text is typed into text fields, or the option picked in selects
+	text string
+	// clicks are the selectors of the radio buttons and checkboxes to click
+	clicks []string
+}
+
+// answer looks up the answer to the question without touching the page.
+func (fl *Filler) answer(f *Field) (answer, bool) {
+	switch f.Kind {
+	case Text, Textarea, Number:
+		value, ok := fl.Bank.Lookup(f.Label)
+		if !ok && fl.Text != nil {
+			value, ok = fl.Text(f)
+		}
+		if !ok {
+			return answer{}, false
+		}
+
+		if f.Kind == Number {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+				log.Warn().Str("label", f.Label).Str("answer", value).Msg("Answer is not a number")
+				return answer{}, false
+			}
+		}
+		return answer{text: value}, true
+
+	case Select:
+		option, ok := fl.Bank.Choose(f.Label, f.OptionLabels())
+		return answer{text: option}, ok
+
+	case Radio:
+		option, ok := fl.Bank.Choose(f.Label, f.OptionLabels())
+		if !ok {
+			return answer{}, false
+		}
+		return answer{clicks: []string{f.Option(option).Selector}}, true
+
+	case Checkbox:
+		if len(f.Options) == 1 {
+			// A lone checkbox is usually an agreement whose label is the question
+			label := f.Label
+			if label == "" {
+				label = f.Options[0].Label
+			}
+
+			value, ok := fl.Bank.Lookup(label)
+			if !ok {
+				return answer{}, false
+			}
+			if !answers.Checked(value) {
+				return answer{}, true
+			}
+			return answer{clicks: []string{f.Options[0].Selector}}, true
+		}
+
+		value, ok := fl.Bank.Lookup(f.Label)
+		if !ok {
+			return answer{}, false
+		}
+
+		// Checkbox groups take a comma separated list of options
+		var a answer
+		for _, v := range strings.Split(value, ",") {
+			if option, ok := answers.Match(v, f.OptionLabels()); ok {
+				a.clicks = append(a.clicks, f.Option(option).Selector)
+			}
+		}
+		return a, len(a.clicks) > 0
+
+	default:
+		return answer{}, false
+	}
+}
+
+// CoverLetterText returns the cover letter to type into the field, if the
+// field asks for one.
+func CoverLetterText(cfg config.CoverLetter, post *datastore.JobPosting, f *Field) (string, bool) {
+	if f.Kind != Textarea || cfg.Text == "" || !coverLetterLabel.MatchString(f.Label) {
+		return "", false
+	}
+
+	return strings.NewReplacer(
+		"{title}", post.Title,
+		"{company}", post.Company,
+	).Replace(cfg.Text), true
+}
+
+// SaveQuestions queues the unanswered questions so they can be answered
+// with `jb questions` before the job is retried, and returns the status the
+// job posting moves to. Questions are stored by label, so a form with an
+// unlabeled question cannot be answered that way and needs a human instead.
+func SaveQuestions(ctx context.Context, ds datastore.Datastore, post *datastore.JobPosting, fields []Field) (string, error) {
+	for _, f := range fields {
+		if strings.TrimSpace(f.Label) == "" {
+			log.Warn().Str("title", post.Title).Str("kind", string(f.Kind)).Msg("Unanswered question has no label")
+			return datastore.StatusNeedsHuman, nil
+		}
+	}
+
+	for _, f := range fields {
+		log.Warn().Str("title", post.Title).Str("question", f.Label).Msg("Unanswered question")
+
+		if err := ds.InsertQuestion(ctx, post.Platform, post.ID, &datastore.Question{
+			Label:   f.Label,
+			Kind:    string(f.Kind),
+			Options: f.OptionLabels(),
+		}); err != nil {
+			return datastore.StatusNeedsAnswer, fmt.Errorf("failed to save question. %w", err)
+		}
+	}
+
+	return datastore.StatusNeedsAnswer, nil
+}
+
+// Exists reports whether the selector matches at least one node.
+func Exists(ctx context.Context, sel string) (bool, error) {
+	var nodes []*pcdp.Node
+	if err := cdp.Run(ctx, cdp.Nodes(sel, &nodes, cdp.ByQuery, cdp.AtLeast(0))); err != nil {
+		return false, err
+	}
+
+	return len(nodes) > 0, nil
+}
+
+// CallJS builds an expression calling fn with the JSON encoded arguments.
+func CallJS(fn string, args ...interface{}) string {
+	encoded := make([]string, 0, len(args))
+	for _, a := range args {
+		b, _ := json.Marshal(a)
+		encoded = append(encoded, string(b))
+	}
+
+	return fn + "(" + strings.Join(encoded, ", ") + ")"
+}
@@ -0,0 +1,111 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package form
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+func TestAnswer(t *testing.T) {
+	bank, err := answers.New([]config.Answer{
+		{Question: `(?i)^email`, Answer: "jane@example.com"},
+		{Question: `(?i)years of experience`, Answer: "five"},
+		{Question: `(?i)notice period`, Answer: "3 months"},
+		{Question: `(?i)authorized to work`, Answer: "Yes"},
+		{Question: `(?i)privacy policy`, Answer: "yes"},
+		{Question: `(?i)newsletter`, Answer: "no"},
+		{Question: `(?i)languages do you speak`, Answer: "English, German"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create answer bank: %v", err)
+	}
+
+	fl := &Filler{
+		Bank: bank,
+		Text: func(f *Field) (string, bool) {
+			return CoverLetterText(config.CoverLetter{Text: "Hello {company}"}, &datastore.JobPosting{Company: "Acme"}, f)
+		},
+	}
+
+	yesNo := []Option{{"Yes", "#yes"}, {"No", "#no"}}
+	for _, tc := range []struct {
+		field Field
+		ok    bool
+		want  answer
+	}{
+		{Field{Kind: Text, Label: "Email *"}, true, answer{text: "jane@example.com"}},
+		{Field{Kind: Number, Label: "Years of experience with Go"}, false, answer{}},
+		{Field{Kind: Textarea, Label: "Cover Letter"}, true, answer{text: "Hello Acme"}},
+		{Field{Kind: Select, Label: "Notice period", Options: []Option{{Label: "1 month"}, {Label: "3 months"}}}, true, answer{text: "3 months"}},
+		{Field{Kind: Radio, Label: "Are you authorized to work in Germany?", Options: yesNo}, true, answer{clicks: []string{"#yes"}}},
+		{Field{Kind: Radio, Label: "Do you need a visa?", Options: yesNo}, false, answer{}},
+		{Field{Kind: Checkbox, Options: []Option{{"I accept the privacy policy", "#privacy"}}}, true, answer{clicks: []string{"#privacy"}}},
+		{Field{Kind: Checkbox, Label: "Subscribe to our newsletter", Options: []Option{{"Yes", "#newsletter"}}}, true, answer{}},
+		{Field{Kind: Checkbox, Label: "Which languages do you speak?", Options: []Option{{"English", "#en"}, {"French", "#fr"}, {"German", "#de"}}}, true, answer{clicks: []string{"#en", "#de"}}},
+		{Field{Kind: File, Label: "Resume"}, false, answer{}},
+	} {
+		got, ok := fl.answer(&tc.field)
+		if ok != tc.ok || got.text != tc.want.text || strings.Join(got.clicks, " ") != strings.Join(tc.want.clicks, " ") {
+			t.Fatalf("answer(%q) = %+v, %v, want %+v, %v", tc.field.Label, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestSaveQuestions(t *testing.T) {
+	ctx := context.Background()
+	post := &datastore.JobPosting{Platform: "linkedin", ID: "1", Title: "Golang Engineer"}
+
+	ds := datastore.NewMemoryDatastore()
+	status, err := SaveQuestions(ctx, ds, post, []Field{
+		{Label: "Notice period?", Kind: Text},
+		{Kind: Select, Options: []Option{{"Yes", "#yes"}}},
+	})
+	if err != nil || status != datastore.StatusNeedsHuman {
+		t.Fatalf("expected a form with an unlabeled question to need a human, got %s: %v", status, err)
+	}
+	if questions, _ := ds.GetQuestions(ctx); len(questions) != 0 {
+		t.Fatalf("expected no question to be stored, got %+v", questions)
+	}
+
+	status, err = SaveQuestions(ctx, ds, post, []Field{
+		{Label: "Notice period?", Kind: Text},
+		{Label: "Authorized to work?", Kind: Radio, Options: []Option{{"Yes", "#yes"}, {"No", "#no"}}},
+	})
+	if err != nil || status != datastore.StatusNeedsAnswer {
+		t.Fatalf("expected the job posting to need an answer, got %s: %v", status, err)
+	}
+	questions, err := ds.GetQuestions(ctx)
+	if err != nil {
+		t.Fatalf("failed to get questions: %v", err)
+	}
+	if len(questions) != 2 || questions[1].Label != "Authorized to work?" || len(questions[1].Options) != 2 {
+		t.Fatalf("expected both questions to be stored, got %+v", questions)
+	}
+}
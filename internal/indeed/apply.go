@@ -0,0 +1,190 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package indeed
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/form"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxApplySteps caps the number of Indeed Apply pages we walk before giving up.
+	maxApplySteps = 12
+	// maxStuckSteps is how many times the same page may come back after
+	// continuing before the form is considered stuck.
+	maxStuckSteps = 2
+
+	applyForm      = `#ia-container`
+	applyHeading   = applyForm + ` h1`
+	applyError     = applyForm + ` [role="alert"]`
+	continueButton = applyForm + ` button.ia-continueButton`
+	resumeUpload   = applyForm + ` input[type="file"]`
+	postApplyPage  = `.ia-PostApply`
+)
+
+// submitLabel matches the continue button of the review page, which sends the application.
+var submitLabel = regexp.MustCompile(`(?i)submit`)
+
+// Apply walks the Indeed Apply form of the open job page until the
+// application is submitted or cannot continue, and returns the status the
+// posting moves to.
+func (i *Indeed) Apply(ctx context.Context, post *datastore.JobPosting) (string, error) {
+	log.Info().Str("title", post.Title).Msg("Applying for job")
+
+	if err := cdp.Run(ctx,
+		cdp.Click(indeedApplyButton, cdp.ByQuery),
+		cdp.WaitVisible(applyForm, cdp.ByQuery),
+	); err != nil {
+		return datastore.StatusFailed, fmt.Errorf("failed to open indeed apply. %w", err)
+	}
+
+	filler := &form.Filler{
+		Bank: i.answers,
+		Text: func(f *form.Field) (string, bool) {
+			return form.CoverLetterText(i.config.CoverLetter, post, f)
+		},
+	}
+
+	uploaded := false
+	lastPage, stuck := "", 0
+	for step := 0; step < maxApplySteps; step++ {
+		var location string
+		if err := cdp.Run(ctx, cdp.Location(&location)); err != nil {
+			return datastore.StatusFailed, err
+		}
+		heading, err := optionalText(ctx, applyHeading)
+		if err != nil {
+			return datastore.StatusFailed, fmt.Errorf("failed to get page heading. %w", err)
+		}
+
+		log.Debug().Str("heading", heading).Str("url", location).Msg("Indeed apply step")
+
+		if page := location + " " + heading; page == lastPage {
+			stuck++
+			if stuck >= maxStuckSteps {
+				log.Warn().Str("title", post.Title).Str("heading", heading).Msg("Indeed apply form is stuck")
+				return datastore.StatusNeedsHuman, nil
+			}
+		} else {
+			lastPage, stuck = page, 0
+		}
+
+		if !uploaded {
+			uploaded, err = i.uploadResume(ctx)
+			if err != nil {
+				return datastore.StatusFailed, err
+			}
+		}
+
+		unanswered, err := filler.Fill(ctx, applyForm)
+		if err != nil {
+			return datastore.StatusFailed, err
+		}
+		if len(unanswered) > 0 {
+			return form.SaveQuestions(ctx, i.ds, post, unanswered)
+		}
+
+		label, err := optionalText(ctx, continueButton)
+		if err != nil {
+			return datastore.StatusFailed, err
+		}
+		if label == "" {
+			log.Warn().Str("title", post.Title).Str("heading", heading).Msg("No indeed apply button found")
+			return datastore.StatusNeedsHuman, nil
+		}
+
+		if err := cdp.Run(ctx, cdp.Click(continueButton, cdp.ByQuery)); err != nil {
+			return datastore.StatusFailed, fmt.Errorf("failed to click on %q button. %w", label, err)
+		}
+
+		if submitLabel.MatchString(label) {
+			return i.submitted(ctx, post)
+		}
+
+		if err := cdp.Run(ctx, cdp.Sleep(1*time.Second)); err != nil {
+			return datastore.StatusFailed, err
+		}
+
+		invalid, err := form.Exists(ctx, applyError)
+		if err != nil {
+			return datastore.StatusFailed, err
+		}
+		if invalid {
+			log.Warn().Str("title", post.Title).Str("heading", heading).Msg("Indeed apply form has errors")
+			return datastore.StatusNeedsHuman, nil
+		}
+	}
+
+	log.Warn().Str("title", post.Title).Int("steps", maxApplySteps).Msg("Indeed apply form has too many steps")
+	return datastore.StatusNeedsHuman, nil
+}
+
+// submitted waits for indeed to confirm the application.
+func (i *Indeed) submitted(ctx context.Context, post *datastore.JobPosting) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := cdp.Run(ctx, cdp.WaitVisible(postApplyPage, cdp.ByQuery)); err != nil {
+		return datastore.StatusFailed, fmt.Errorf("failed to submit application. %w", err)
+	}
+
+	log.Info().Str("title", post.Title).Msg("Application submitted")
+	return datastore.StatusSubmitted, nil
+}
+
+// uploadResume uploads the configured resume when the page asks for one,
+// and reports whether it did.
+func (i *Indeed) uploadResume(ctx context.Context) (bool, error) {
+	if i.config.Resume == "" {
+		return false, nil
+	}
+
+	upload, err := form.Exists(ctx, resumeUpload)
+	if err != nil || !upload {
+		return false, err
+	}
+
+	path, err := filepath.Abs(i.config.Resume)
+	if err != nil {
+		return false, err
+	}
+
+	log.Info().Str("resume", path).Msg("Uploading resume")
+	if err := cdp.Run(ctx,
+		cdp.SetUploadFiles(resumeUpload, []string{path}, cdp.ByQuery),
+		cdp.Sleep(2*time.Second),
+	); err != nil {
+		return false, fmt.Errorf("failed to upload resume. %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,372 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package indeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	pcdp "github.com/chromedp/cdproto/cdp"
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/filter"
+	"github.com/k1ng440/job-bot/internal/form"
+	"github.com/k1ng440/job-bot/internal/platform"
+	"github.com/k1ng440/job-bot/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+type Indeed struct {
+	ds       datastore.Datastore
+	config   config.Indeed
+	filters  *filter.PostingFilter
+	answers  *answers.Bank
+	searches []*jobSearch
+}
+
+var (
+	ErrNotSignedIn  = errors.New("not signed in")
+	workplaceRegex  = regexp.MustCompile(`(?i)\b(remote|hybrid)\b`)
+	employmentRegex = regexp.MustCompile(`(?i)full-time|part-time|contract|temporary|internship`)
+)
+
+const (
+	Name = "indeed"
+
+	defaultBaseUrl = "https://www.indeed.com"
+
+	// pageSize is the offset indeed moves by per search page
+	pageSize = 10
+	// maxSearchResults is the last offset searched
+	maxSearchResults = 1000
+	// loginChecks is how many times the sign in is checked, 10 seconds apart,
+	// before giving up
+	loginChecks = 30
+
+	signInLink        = `a[href*="/account/login"]`
+	jobCardLink       = `a.jcs-JobTitle[data-jk]`
+	nextPageLink      = `a[data-testid="pagination-page-next"]`
+	jobTitle          = `h1.jobsearch-JobInfoHeader-title`
+	jobCompany        = `[data-testid="inlineHeader-companyName"]`
+	jobLocation       = `[data-testid="inlineHeader-companyLocation"]`
+	jobSalary         = `#salaryInfoAndJobType > span:first-child`
+	jobType           = `#salaryInfoAndJobType`
+	jobFooter         = `[data-testid="jobsearch-JobMetadataFooter"]`
+	jobDesc           = `#jobDescriptionText`
+	indeedApplyButton = `#indeedApplyButton`
+)
+
+func init() {
+	platform.Register(Name, func(cfg config.Config, bank *answers.Bank, ds datastore.Datastore) (platform.Platform, error) {
+		return New(cfg.Indeed, bank, ds)
+	})
+}
+
+func New(cfg config.Indeed, bank *answers.Bank, ds datastore.Datastore) (*Indeed, error) {
+	if cfg.BaseUrl == "" {
+		cfg.BaseUrl = defaultBaseUrl
+	}
+	cfg.BaseUrl = strings.TrimSuffix(cfg.BaseUrl, "/")
+
+	filters, err := filter.NewPostingFilter(cfg.PostingFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	searches, err := newSearches(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Indeed{
+		ds:       ds,
+		config:   cfg,
+		filters:  filters,
+		answers:  bank,
+		searches: searches,
+	}, nil
+}
+
+// Filters returns the posting filters of the job posting, which every indeed
+// search shares.
+func (i *Indeed) Filters(*datastore.JobPosting) *filter.PostingFilter {
+	return i.filters
+}
+
+func (i *Indeed) Name() string {
+	return Name
+}
+
+func (i *Indeed) Limits() platform.Limits {
+	return platform.Limits{
+		MaxApplications:           i.config.MaxApplications,
+		MaxApplicationsPerCompany: i.config.MaxApplicationsPerCompany,
+	}
+}
+
+// Login waits for the browser to be signed in to indeed. Indeed signs in
+// with one time codes sent by email, so it is done by hand and the session
+// is kept in the chrome profile.
+func (i *Indeed) Login(ctx context.Context) error {
+	if err := cdp.Run(ctx, cdp.Navigate(i.config.BaseUrl)); err != nil {
+		return fmt.Errorf("failed to open indeed. %w", err)
+	}
+
+	for n := 0; n < loginChecks; n++ {
+		signedOut, err := form.Exists(ctx, signInLink)
+		if err != nil {
+			return fmt.Errorf("failed to check indeed sign in. %w", err)
+		}
+		if !signedOut {
+			log.Info().Msg("Signed in to indeed")
+			return nil
+		}
+
+		if n == 0 {
+			log.Warn().Msg("Sign in to indeed in the browser window to continue")
+		}
+		if err := cdp.Run(ctx, cdp.Sleep(10*time.Second)); err != nil {
+			return err
+		}
+	}
+
+	return errors.Join(
+		ErrNotSignedIn,
+		errors.New("sign in to indeed with a non headless browser. The session is kept in the chrome profile"),
+	)
+}
+
+// Search runs every search and passes the eligible jobs to found as they
+// are stored.
+func (i *Indeed) Search(ctx context.Context, found platform.Found) error {
+	for _, s := range i.searches {
+		log.Info().Str("search", s.name).Str("url", s.url).Msg("searching for jobs")
+		if err := i.search(ctx, s, found); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Indeed) search(ctx context.Context, s *jobSearch, found platform.Found) error {
+	for start := 0; start < maxSearchResults; start += pageSize {
+		ids, next, err := i.visitSearchPage(ctx, s, start)
+		if err != nil {
+			return err
+		}
+
+		log.Info().Int("start", start).Int("cards", len(ids)).Msg("Iterating over jobs")
+
+		for _, id := range ids {
+			if err := i.visitJob(ctx, s, id, found); err != nil {
+				return err
+			}
+		}
+
+		if !next || len(ids) == 0 {
+			break
+		}
+	}
+
+	return nil
+}
+
+// visitSearchPage opens the search page at the offset and returns the ids
+// of the jobs on it and whether there is a next page.
+func (i *Indeed) visitSearchPage(ctx context.Context, s *jobSearch, start int) ([]string, bool, error) {
+	var cards []*pcdp.Node
+	if err := cdp.Run(ctx,
+		cdp.Navigate(s.pageUrl(start)),
+		cdp.WaitReady(`body`, cdp.ByQuery),
+		cdp.Nodes(jobCardLink, &cards, cdp.ByQuery, cdp.AtLeast(0)),
+	); err != nil {
+		return nil, false, fmt.Errorf("failed to navigate to search page. %w", err)
+	}
+
+	ids := make([]string, 0, len(cards))
+	for _, c := range cards {
+		ids = append(ids, c.AttributeValue("data-jk"))
+	}
+
+	next, err := form.Exists(ctx, nextPageLink)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up next page. %w", err)
+	}
+
+	return ids, next, nil
+}
+
+// visitJob opens the job page, stores the posting and passes it to found
+// when eligible.
+func (i *Indeed) visitJob(ctx context.Context, s *jobSearch, id string, found platform.Found) error {
+	if err := cdp.Run(ctx,
+		cdp.Navigate(i.jobUrl(id)),
+		cdp.WaitVisible(jobTitle, cdp.ByQuery),
+	); err != nil {
+		return fmt.Errorf("failed to open job %s. %w", id, err)
+	}
+
+	post, err := i.scrapeJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	post.Search = s.name
+
+	indeedApply, err := form.Exists(ctx, indeedApplyButton)
+	if err != nil {
+		return err
+	}
+
+	// Rejected postings are stored as well so the reason can be audited
+	result := i.filters.Evaluate(post)
+	switch {
+	case !indeedApply:
+		log.Debug().Str("title", post.Title).Msg("Job has no Indeed Apply")
+		post.Status = datastore.StatusFiltered
+		post.FilterReason = "apply: no indeed apply"
+	case !result.Allowed:
+		log.Debug().Str("title", post.Title).Str("reason", result.Reason()).Msg("Job blacklisted")
+		post.Status = datastore.StatusFiltered
+		post.FilterReason = result.Reason()
+	default:
+		log.Debug().Str("title", post.Title).Msg("Job allowed")
+		post.Status = datastore.StatusQueued
+	}
+
+	err = i.ds.InsertJobPosting(ctx, post)
+	if errors.Is(err, datastore.ErrAlreadyExists) {
+		log.Debug().Str("id", post.ID).Msg("Job posting already stored")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert job posting. %w", err)
+	}
+
+	if post.Status != datastore.StatusQueued {
+		return nil
+	}
+
+	return found(ctx, post)
+}
+
+// scrapeJob reads the job posting from the job page.
+func (i *Indeed) scrapeJob(ctx context.Context, id string) (*datastore.JobPosting, error) {
+	post, err := ScrapeJobDetails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Url = i.jobUrl(id)
+	return post, nil
+}
+
+// ScrapeJobDetails reads the job posting with the given id from the job page
+// shown in the current tab.
+func ScrapeJobDetails(ctx context.Context, id string) (*datastore.JobPosting, error) {
+	var title, company, location, salary, jobTypes, footer, description string
+	for _, t := range []struct {
+		sel  string
+		text *string
+	}{
+		{jobTitle, &title},
+		{jobCompany, &company},
+		{jobLocation, &location},
+		{jobSalary, &salary},
+		{jobType, &jobTypes},
+		{jobFooter, &footer},
+		{jobDesc, &description},
+	} {
+		text, err := optionalText(ctx, t.sel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job details. %w", err)
+		}
+		*t.text = text
+	}
+
+	if title == "" {
+		return nil, fmt.Errorf("failed to get job details of %s. title not found", id)
+	}
+
+	post := &datastore.JobPosting{
+		Platform:       Name,
+		ID:             id,
+		Title:          strings.TrimSpace(strings.TrimSuffix(title, "- job post")),
+		Company:        company,
+		Location:       location,
+		WorkplaceType:  strings.ToLower(workplaceRegex.FindString(location)),
+		EmploymentType: strings.ToLower(employmentRegex.FindString(jobTypes)),
+		Description:    description,
+	}
+
+	if _, ok := utils.ParseSalary(salary); ok {
+		post.Salary = salary
+	}
+
+	if postedAt, ok := utils.ParsePostedAgo(footer, time.Now()); ok {
+		post.PostedAt = postedAt
+	}
+
+	return post, nil
+}
+
+// FetchDetails navigates to the job page and waits for the Indeed Apply
+// button. Jobs no longer accepting applications have none, so it gives up
+// after a while.
+func (i *Indeed) FetchDetails(ctx context.Context, post *datastore.JobPosting) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return cdp.Run(ctx,
+		cdp.Navigate(i.jobUrl(post.ID)),
+		cdp.WaitVisible(indeedApplyButton, cdp.ByQuery),
+	)
+}
+
+func (i *Indeed) jobUrl(id string) string {
+	return i.config.BaseUrl + "/viewjob?jk=" + url.QueryEscape(id)
+}
+
+// optionalText returns the text of the first node matching the selector,
+// or an empty string when nothing matches.
+func optionalText(ctx context.Context, sel string) (string, error) {
+	ok, err := form.Exists(ctx, sel)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	var text string
+	if err := cdp.Run(ctx, cdp.Text(sel, &text, cdp.ByQuery)); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(text), nil
+}
@@ -0,0 +1,167 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package indeed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/browsertest"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+// newServer serves the saved indeed pages in testdata.
+func newServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/", browsertest.Page("home"))
+	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "10" {
+			browsertest.Page("search_2")(w, r)
+			return
+		}
+		browsertest.Page("search_1")(w, r)
+	})
+	mux.HandleFunc("/viewjob", func(w http.ResponseWriter, r *http.Request) {
+		browsertest.Page("job_"+path.Base(r.URL.Query().Get("jk")))(w, r)
+	})
+	mux.HandleFunc("/apply/", func(w http.ResponseWriter, r *http.Request) {
+		browsertest.Page("apply_"+path.Base(r.URL.Path))(w, r)
+	})
+
+	return browsertest.NewServer(t, mux)
+}
+
+func newIndeed(t *testing.T, srv *httptest.Server, bank []config.Answer, ds datastore.Datastore) *Indeed {
+	b, err := answers.New(bank)
+	if err != nil {
+		t.Fatalf("failed to create answer bank: %v", err)
+	}
+
+	i, err := New(config.Indeed{
+		BaseUrl:  srv.URL,
+		Searches: []config.IndeedSearch{{Name: "Go", Keywords: "golang"}},
+		PostingFilters: config.PostingFilters{
+			Blacklists: config.Patterns{Title: []string{`(?i)java`}},
+		},
+	}, b, ds)
+	if err != nil {
+		t.Fatalf("failed to create indeed bot: %v", err)
+	}
+
+	return i
+}
+
+func TestSearch(t *testing.T) {
+	ctx := browsertest.NewBrowser(t)
+	ds := datastore.NewMemoryDatastore()
+	i := newIndeed(t, newServer(t), nil, ds)
+
+	if err := i.Login(ctx); err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+
+	var found []string
+	if err := i.Search(ctx, func(_ context.Context, post *datastore.JobPosting) error {
+		found = append(found, post.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+
+	if len(found) != 1 || found[0] != "aaa" {
+		t.Fatalf("expected only aaa to be found, got %v", found)
+	}
+
+	posts, err := ds.GetJobPostings(ctx)
+	if err != nil {
+		t.Fatalf("failed to get job postings: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("expected the jobs of both search pages to be stored, got %d", len(posts))
+	}
+
+	reasons := map[string]string{}
+	for _, post := range posts {
+		reasons[post.ID] = post.FilterReason
+		if post.ID != "aaa" {
+			continue
+		}
+
+		if post.Platform != Name || post.Search != "Go" || post.Title != "Golang Engineer" || post.Company != "Acme" ||
+			post.Location != "Remote in Berlin" || post.WorkplaceType != "remote" || post.EmploymentType != "full-time" ||
+			post.Salary != "€60,000 - €75,000 a year" || post.PostedAt.IsZero() || post.Description == "" {
+			t.Fatalf("unexpected job details %+v", post)
+		}
+	}
+
+	if reasons["bbb"] != "title: (?i)java" || reasons["ccc"] != "apply: no indeed apply" {
+		t.Fatalf("unexpected filter reasons %v", reasons)
+	}
+}
+
+func TestApply(t *testing.T) {
+	ctx := browsertest.NewBrowser(t)
+	srv := newServer(t)
+	post := &datastore.JobPosting{Platform: Name, ID: "aaa", Title: "Golang Engineer", Company: "Acme"}
+
+	ds := datastore.NewMemoryDatastore()
+	i := newIndeed(t, srv, []config.Answer{
+		{Question: `(?i)years of experience`, Answer: "5"},
+		{Question: `(?i)authorized to work`, Answer: "Yes"},
+	}, ds)
+
+	if err := i.FetchDetails(ctx, post); err != nil {
+		t.Fatalf("failed to open job: %v", err)
+	}
+	if status, err := i.Apply(ctx, post); err != nil || status != datastore.StatusSubmitted {
+		t.Fatalf("expected the application to be submitted, got %s: %v", status, err)
+	}
+
+	// Without an answer the question is queued for `jb questions`
+	ds = datastore.NewMemoryDatastore()
+	if err := ds.InsertJobPosting(ctx, post); err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+	i = newIndeed(t, srv, []config.Answer{{Question: `(?i)years of experience`, Answer: "5"}}, ds)
+
+	if err := i.FetchDetails(ctx, post); err != nil {
+		t.Fatalf("failed to open job: %v", err)
+	}
+	if status, err := i.Apply(ctx, post); err != nil || status != datastore.StatusNeedsAnswer {
+		t.Fatalf("expected the application to need an answer, got %s: %v", status, err)
+	}
+
+	questions, err := ds.GetQuestions(ctx)
+	if err != nil {
+		t.Fatalf("failed to get questions: %v", err)
+	}
+	if len(questions) != 1 || questions[0].Label != "Are you authorized to work in Germany?" || len(questions[0].Options) != 2 {
+		t.Fatalf("expected the work authorization question to be queued, got %+v", questions)
+	}
+}
@@ -0,0 +1,121 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package indeed
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/k1ng440/job-bot/internal/config"
+)
+
+// indeedApplyFilter limits the search results to Indeed Apply jobs.
+const indeedApplyFilter = "0kf:attr(DSQF7);"
+
+// The values of the search url filters, keyed by the normalized config value.
+var (
+	datePostedFilters = map[string]string{
+		"any":    "",
+		"day":    "1",
+		"3days":  "3",
+		"week":   "7",
+		"2weeks": "14",
+	}
+	sortByFilters = map[string]string{
+		"relevant": "",
+		"recent":   "date",
+	}
+)
+
+// jobSearch is a search url to crawl.
+type jobSearch struct {
+	name string
+	url  string
+}
+
+func newSearches(cfg config.Indeed) ([]*jobSearch, error) {
+	searches := make([]*jobSearch, 0, len(cfg.Searches))
+	for n, s := range cfg.Searches {
+		u, err := searchUrl(cfg.BaseUrl, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search %d. %w", n+1, err)
+		}
+
+		name := s.Name
+		if name == "" {
+			name = u
+		}
+		searches = append(searches, &jobSearch{name: name, url: u})
+	}
+
+	return searches, nil
+}
+
+// searchUrl builds the indeed search url of a search. Paging is added by pageUrl.
+func searchUrl(baseUrl string, s config.IndeedSearch) (string, error) {
+	query := url.Values{}
+	query.Set("q", s.Keywords)
+	if s.Location != "" {
+		query.Set("l", s.Location)
+	}
+	if s.Radius > 0 {
+		query.Set("radius", strconv.Itoa(s.Radius))
+	}
+
+	for _, f := range []struct {
+		param   string
+		name    string
+		value   string
+		filters map[string]string
+	}{
+		{"fromage", "date posted", s.DatePosted, datePostedFilters},
+		{"sort", "sort order", s.SortBy, sortByFilters},
+	} {
+		if f.value == "" {
+			continue
+		}
+
+		key := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(f.value))
+		v, ok := f.filters[key]
+		if !ok {
+			return "", fmt.Errorf("unknown %s %q", f.name, f.value)
+		}
+		if v != "" {
+			query.Set(f.param, v)
+		}
+	}
+
+	query.Set("sc", indeedApplyFilter)
+	return baseUrl + "/jobs?" + query.Encode(), nil
+}
+
+// pageUrl returns the url of the search page at the offset.
+func (s *jobSearch) pageUrl(start int) string {
+	if start == 0 {
+		return s.url
+	}
+	return s.url + "&start=" + strconv.Itoa(start)
+}
@@ -0,0 +1,90 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package indeed
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+)
+
+func TestSearchUrl(t *testing.T) {
+	u, err := searchUrl("https://de.indeed.com", config.IndeedSearch{
+		Keywords:   "golang",
+		Location:   "Berlin",
+		Radius:     25,
+		DatePosted: "3 days",
+		SortBy:     "Recent",
+	})
+	if err != nil {
+		t.Fatalf("failed to build search url: %v", err)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("failed to parse url %q: %v", u, err)
+	}
+	if parsed.Host != "de.indeed.com" || parsed.Path != "/jobs" {
+		t.Fatalf("unexpected search url %q", u)
+	}
+
+	query := parsed.Query()
+	for param, want := range map[string]string{
+		"q":       "golang",
+		"l":       "Berlin",
+		"radius":  "25",
+		"fromage": "3",
+		"sort":    "date",
+		"sc":      indeedApplyFilter,
+	} {
+		if got := query.Get(param); got != want {
+			t.Fatalf("expected %s=%q, got %q", param, want, got)
+		}
+	}
+
+	if _, err := searchUrl(defaultBaseUrl, config.IndeedSearch{DatePosted: "month"}); err == nil {
+		t.Fatal("expected an unsupported date posted filter to fail")
+	}
+}
+
+func TestNewSearches(t *testing.T) {
+	i, err := New(config.Indeed{
+		BaseUrl:  "https://de.indeed.com/",
+		Searches: []config.IndeedSearch{{Keywords: "golang"}, {Name: "SRE", Keywords: "sre"}},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create indeed bot: %v", err)
+	}
+
+	if i.searches[0].name != i.searches[0].url || i.searches[1].name != "SRE" {
+		t.Fatalf("expected the search url to name unnamed searches, got %+v", i.searches)
+	}
+	if got := i.searches[1].pageUrl(20); got != "https://de.indeed.com/jobs?q=sre&sc=0kf%3Aattr%28DSQF7%29%3B&start=20" {
+		t.Fatalf("unexpected page url %q", got)
+	}
+	if got := i.jobUrl("a1b2"); got != "https://de.indeed.com/viewjob?jk=a1b2" {
+		t.Fatalf("unexpected job url %q", got)
+	}
+}
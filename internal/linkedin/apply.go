@@ -0,0 +1,321 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pcdp "github.com/chromedp/cdproto/cdp"
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/form"
+	"github.com/rs/zerolog/log"
+)
+
+// Outcome is the final result of walking the Easy Apply modal. Each outcome
+// is the status the job posting moves to.
+type Outcome string
+
+const (
+	// OutcomeSubmitted means the application was sent.
+	OutcomeSubmitted Outcome = datastore.StatusSubmitted
+	// OutcomeAbandoned means the modal was closed without submitting.
+	OutcomeAbandoned Outcome = datastore.StatusFailed
+	// OutcomeNeedsHuman means the modal could not be completed automatically.
+	OutcomeNeedsHuman Outcome = datastore.StatusNeedsHuman
+	// OutcomeNeedsAnswer means the form asked questions missing from the
+	// answer bank. They are stored so the job can be retried once answered.
+	OutcomeNeedsAnswer Outcome = datastore.StatusNeedsAnswer
+)
+
+const (
+	// maxApplySteps caps the number of modal pages we walk before giving up.
+	maxApplySteps = 12
+	// maxStuckSteps is how many times the same page may come back after
+	// clicking through it before the modal is considered stuck.
+	maxStuckSteps = 2
+
+	applyButton     = `button.jobs-apply-button`
+	easyApplyModal  = `div.jobs-easy-apply-modal`
+	modalHeader     = easyApplyModal + ` h3`
+	modalProgress   = easyApplyModal + ` progress`
+	modalFormError  = easyApplyModal + ` .artdeco-inline-feedback--error`
+	nextButton      = easyApplyModal + ` button[aria-label="Continue to next step"]`
+	reviewButton    = easyApplyModal + ` button[aria-label="Review your application"]`
+	submitButton    = easyApplyModal + ` button[aria-label="Submit application"]`
+	followCheckbox  = easyApplyModal + ` input#follow-company-checkbox`
+	dismissButton   = `button[aria-label="Dismiss"]`
+	discardButton   = `button[data-control-name="discard_application_confirm_btn"]`
+	postApplyDialog = `div[aria-labelledby="post-apply-modal"]`
+)
+
+// step is the action offered by the current page of the Easy Apply modal.
+type step int
+
+const (
+	stepUnknown step = iota
+	stepNext
+	stepReview
+	stepSubmit
+)
+
+func (s step) String() string {
+	switch s {
+	case stepNext:
+		return "next"
+	case stepReview:
+		return "review"
+	case stepSubmit:
+		return "submit"
+	default:
+		return "unknown"
+	}
+}
+
+// apply opens the Easy Apply modal for the currently selected job and walks
+// it page by page until the application is submitted or cannot continue.
+func (l *Linkedin) apply(ctx context.Context, post *datastore.JobPosting) (Outcome, error) {
+	log.Info().Str("title", post.Title).Msg("Applying for job")
+
+	if err := cdp.Run(ctx,
+		cdp.Click(applyButton, cdp.ByQuery),
+		cdp.WaitVisible(easyApplyModal, cdp.ByQuery),
+	); err != nil {
+		return OutcomeAbandoned, fmt.Errorf("failed to open easy apply modal. %w", err)
+	}
+
+	log.Debug().Msg("Easy apply modal opened")
+
+	lastHeader, lastProgress, stuck := "", -1, 0
+	for i := 0; i < maxApplySteps; i++ {
+		header, err := l.modalHeader(ctx)
+		if err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, err)
+		}
+
+		progress, err := l.modalProgress(ctx)
+		if err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, err)
+		}
+
+		s, err := l.currentStep(ctx)
+		if err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, err)
+		}
+
+		log.Debug().
+			Str("header", header).
+			Int("progress", progress).
+			Stringer("step", s).
+			Msg("Easy apply step")
+
+		// Some pages have no progress bar, so the header is compared as well
+		if progress == lastProgress && header == lastHeader {
+			stuck++
+			if stuck >= maxStuckSteps {
+				log.Warn().Str("title", post.Title).Str("header", header).Msg("Easy apply modal is stuck")
+				return l.abandon(ctx, OutcomeNeedsHuman, nil)
+			}
+		} else {
+			stuck = 0
+		}
+		lastHeader, lastProgress = header, progress
+
+		if s == stepNext || s == stepReview {
+			if err := l.selectResume(ctx, post); err != nil {
+				return l.abandon(ctx, OutcomeAbandoned, err)
+			}
+
+			if err := l.uploadCoverLetter(ctx, post); err != nil {
+				return l.abandon(ctx, OutcomeAbandoned, err)
+			}
+
+			unanswered, err := l.fillForm(ctx, post)
+			if err != nil {
+				return l.abandon(ctx, OutcomeAbandoned, err)
+			}
+			if len(unanswered) > 0 {
+				status, err := form.SaveQuestions(ctx, l.ds, post, unanswered)
+				return l.abandon(ctx, Outcome(status), err)
+			}
+		}
+
+		switch s {
+		case stepNext:
+			err = cdp.Run(ctx, cdp.Click(nextButton, cdp.ByQuery))
+		case stepReview:
+			err = cdp.Run(ctx, cdp.Click(reviewButton, cdp.ByQuery))
+		case stepSubmit:
+			return l.submit(ctx, post)
+		default:
+			log.Warn().Str("title", post.Title).Str("header", header).Msg("No easy apply button found")
+			return l.abandon(ctx, OutcomeNeedsHuman, nil)
+		}
+		if err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, fmt.Errorf("failed to click on %s button. %w", s, err))
+		}
+
+		if err := cdp.Run(ctx, cdp.Sleep(1*time.Second)); err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, err)
+		}
+
+		invalid, err := l.exists(ctx, modalFormError)
+		if err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, err)
+		}
+		if invalid {
+			log.Warn().Str("title", post.Title).Str("header", header).Msg("Easy apply form has errors")
+			return l.abandon(ctx, OutcomeNeedsHuman, nil)
+		}
+	}
+
+	log.Warn().Str("title", post.Title).Int("steps", maxApplySteps).Msg("Easy apply modal has too many steps")
+	return l.abandon(ctx, OutcomeNeedsHuman, nil)
+}
+
+// submit unfollows the company and sends the application.
+func (l *Linkedin) submit(ctx context.Context, post *datastore.JobPosting) (Outcome, error) {
+	follow, err := l.exists(ctx, followCheckbox)
+	if err != nil {
+		return l.abandon(ctx, OutcomeAbandoned, err)
+	}
+
+	if follow {
+		var checked bool
+		if err := cdp.Run(ctx,
+			cdp.Evaluate(`document.querySelector('`+followCheckbox+`').checked`, &checked),
+		); err != nil {
+			return l.abandon(ctx, OutcomeAbandoned, err)
+		}
+
+		if checked {
+			if err := cdp.Run(ctx, cdp.Click(followCheckbox, cdp.ByQuery)); err != nil {
+				return l.abandon(ctx, OutcomeAbandoned, fmt.Errorf("failed to unfollow company. %w", err))
+			}
+		}
+	}
+
+	if err := cdp.Run(ctx,
+		cdp.Click(submitButton, cdp.ByQuery),
+		cdp.WaitNotPresent(easyApplyModal, cdp.ByQuery),
+	); err != nil {
+		return OutcomeAbandoned, fmt.Errorf("failed to submit application. %w", err)
+	}
+
+	// The confirmation dialog only shows up some of the time
+	dialog, err := l.exists(ctx, postApplyDialog)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to look up post apply dialog")
+	}
+	if dialog {
+		if err := cdp.Run(ctx, cdp.Click(dismissButton, cdp.ByQuery)); err != nil {
+			log.Warn().Err(err).Msg("Failed to dismiss post apply dialog")
+		}
+	}
+
+	log.Info().Str("title", post.Title).Msg("Application submitted")
+	return OutcomeSubmitted, nil
+}
+
+// abandon closes the modal, discards the draft and returns the given outcome.
+func (l *Linkedin) abandon(ctx context.Context, outcome Outcome, cause error) (Outcome, error) {
+	// The discard confirmation does not always show up
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := cdp.Run(ctx,
+		cdp.Click(dismissButton, cdp.ByQuery),
+		cdp.WaitVisible(discardButton, cdp.ByQuery),
+		cdp.Click(discardButton, cdp.ByQuery),
+		cdp.WaitNotPresent(easyApplyModal, cdp.ByQuery),
+	); err != nil {
+		log.Warn().Err(err).Msg("Failed to discard application")
+	}
+
+	return outcome, cause
+}
+
+// currentStep reports which primary button the modal currently shows.
+func (l *Linkedin) currentStep(ctx context.Context) (step, error) {
+	for _, c := range []struct {
+		sel  string
+		step step
+	}{
+		{submitButton, stepSubmit},
+		{reviewButton, stepReview},
+		{nextButton, stepNext},
+	} {
+		ok, err := l.exists(ctx, c.sel)
+		if err != nil {
+			return stepUnknown, err
+		}
+		if ok {
+			return c.step, nil
+		}
+	}
+
+	return stepUnknown, nil
+}
+
+// modalHeader returns the title of the current modal page.
+func (l *Linkedin) modalHeader(ctx context.Context) (string, error) {
+	header, err := optionalText(ctx, modalHeader)
+	if err != nil {
+		return "", fmt.Errorf("failed to get modal header. %w", err)
+	}
+
+	return header, nil
+}
+
+// modalProgress returns the progress bar value in percent,
+// or 0 when the modal has no progress bar.
+func (l *Linkedin) modalProgress(ctx context.Context) (int, error) {
+	var nodes []*pcdp.Node
+	if err := cdp.Run(ctx, cdp.Nodes(modalProgress, &nodes, cdp.ByQuery, cdp.AtLeast(0))); err != nil {
+		return 0, fmt.Errorf("failed to get progress bar. %w", err)
+	}
+	if len(nodes) == 0 {
+		return 0, nil
+	}
+
+	progress, err := strconv.ParseFloat(nodes[0].AttributeValue("value"), 64)
+	if err != nil {
+		return 0, nil
+	}
+
+	return int(progress), nil
+}
+
+// exists reports whether the selector matches at least one node.
+func (l *Linkedin) exists(ctx context.Context, sel string) (bool, error) {
+	var nodes []*pcdp.Node
+	if err := cdp.Run(ctx, cdp.Nodes(sel, &nodes, cdp.ByQuery, cdp.AtLeast(0))); err != nil {
+		return false, err
+	}
+
+	return len(nodes) > 0, nil
+}
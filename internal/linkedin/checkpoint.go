@@ -0,0 +1,139 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultCheckpointExpiry is used when CheckpointExpiryHours is not set.
+const defaultCheckpointExpiry = 24 * time.Hour
+
+// crawl is the progress of a search url, saved as a checkpoint after every
+// job so an interrupted crawl resumes where it left off.
+type crawl struct {
+	url   string
+	start int
+	// seen are the jobs visited on the current and previous page. Linkedin
+	// shifts the results as new jobs are posted, so a resumed page may show
+	// jobs that were already visited.
+	seen []string
+}
+
+func (c *crawl) visited(id string) bool {
+	for _, s := range c.seen {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// next moves the crawl to the next search page.
+func (c *crawl) next() {
+	c.start += pageSize
+	if len(c.seen) > pageSize {
+		c.seen = c.seen[len(c.seen)-pageSize:]
+	}
+}
+
+// lastPage reports whether the search page at start, showing the number of
+// cards, is the last one linkedin serves for the available jobs.
+func lastPage(start, cards, available int) bool {
+	// A short page is the last one
+	return cards < pageSize || start+pageSize >= available || start+pageSize >= maxSearchResults
+}
+
+// resumeCrawl returns the crawl of the search url, starting from its
+// checkpoint unless there is none or it has expired.
+func (l *Linkedin) resumeCrawl(ctx context.Context, u string) (*crawl, error) {
+	c := &crawl{url: u}
+
+	checkpoint, err := l.ds.GetCheckpoint(ctx, Name, u)
+	if errors.Is(err, datastore.ErrNotFound) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint. %w", err)
+	}
+
+	expiry := defaultCheckpointExpiry
+	if l.config.CheckpointExpiryHours > 0 {
+		expiry = time.Duration(l.config.CheckpointExpiryHours) * time.Hour
+	}
+	if time.Since(checkpoint.UpdatedAt) > expiry {
+		log.Info().Str("url", u).Time("updated_at", checkpoint.UpdatedAt).Msg("Checkpoint expired. Starting over")
+		return c, nil
+	}
+
+	log.Info().Str("url", u).Int("start", checkpoint.Start).Msg("Resuming search from checkpoint")
+	c.start = checkpoint.Start
+	c.seen = checkpoint.JobIDs
+	return c, nil
+}
+
+// saveCrawl stores the checkpoint of the crawl.
+func (l *Linkedin) saveCrawl(ctx context.Context, c *crawl) error {
+	if err := l.ds.SaveCheckpoint(ctx, &datastore.Checkpoint{
+		Platform: Name,
+		Url:      c.url,
+		Start:    c.start,
+		JobIDs:   c.seen,
+	}); err != nil {
+		return fmt.Errorf("failed to save checkpoint. %w", err)
+	}
+
+	return nil
+}
+
+// finishCrawl removes the checkpoint of a fully crawled search url,
+// so the next run starts from the first page.
+func (l *Linkedin) finishCrawl(ctx context.Context, c *crawl) error {
+	if err := l.ds.DeleteCheckpoint(ctx, Name, c.url); err != nil {
+		return fmt.Errorf("failed to delete checkpoint. %w", err)
+	}
+
+	return nil
+}
+
+// jobCardID returns the job id of the job card at the 1-based index of the
+// current search page, or an empty string when the card has none.
+func (l *Linkedin) jobCardID(ctx context.Context, index int) (string, error) {
+	var id string
+	var ok bool
+	if err := cdp.Run(ctx,
+		cdp.AttributeValue(fmt.Sprintf(jobCardXPath, index), "data-job-id", &id, &ok, cdp.AtLeast(0)),
+	); err != nil {
+		return "", fmt.Errorf("failed to get job id. %w", err)
+	}
+
+	return id, nil
+}
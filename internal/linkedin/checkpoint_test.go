@@ -0,0 +1,130 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+func TestResumeCrawl(t *testing.T) {
+	ctx := context.Background()
+	url := "https://www.linkedin.com/jobs/search/?keywords=golang"
+
+	l, err := New(config.Linkedin{}, nil, datastore.NewMemoryDatastore())
+	if err != nil {
+		t.Fatalf("failed to create linkedin bot: %v", err)
+	}
+
+	c, err := l.resumeCrawl(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to resume crawl: %v", err)
+	}
+	if c.start != 0 || len(c.seen) != 0 {
+		t.Fatalf("expected a new crawl, got %+v", c)
+	}
+
+	c.seen = append(c.seen, "1", "2")
+	c.next()
+	if err := l.saveCrawl(ctx, c); err != nil {
+		t.Fatalf("failed to save crawl: %v", err)
+	}
+
+	resumed, err := l.resumeCrawl(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to resume crawl: %v", err)
+	}
+	if resumed.start != pageSize || !resumed.visited("2") || resumed.visited("3") {
+		t.Fatalf("expected the crawl to resume from the checkpoint, got %+v", resumed)
+	}
+
+	if err := l.finishCrawl(ctx, resumed); err != nil {
+		t.Fatalf("failed to finish crawl: %v", err)
+	}
+
+	c, err = l.resumeCrawl(ctx, url)
+	if err != nil {
+		t.Fatalf("failed to resume crawl: %v", err)
+	}
+	if c.start != 0 {
+		t.Fatalf("expected a finished crawl to start over, got %+v", c)
+	}
+}
+
+func TestLastPage(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		start     int
+		available int
+		// cards is the number of cards on every page, or pageSize when 0
+		cards int
+		pages []int
+	}{
+		{"no jobs", 0, 0, 0, []int{0}},
+		{"one full page", 0, pageSize, 0, []int{0}},
+		{"one more job", 0, pageSize + 1, 0, []int{0, pageSize}},
+		{"two full pages", 0, 2 * pageSize, 0, []int{0, pageSize}},
+		{"short page", 0, 100, 10, []int{0}},
+		{"resumed from checkpoint", 2 * pageSize, 100, 0, []int{2 * pageSize, 3 * pageSize}},
+		{"resumed on the last page", 3 * pageSize, 100, 0, []int{3 * pageSize}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var pages []int
+			for c := (&crawl{start: tc.start}); c.start < maxSearchResults; c.next() {
+				pages = append(pages, c.start)
+
+				cards := tc.cards
+				if cards == 0 {
+					cards = pageSize
+					if left := tc.available - c.start; left < cards {
+						cards = left
+					}
+				}
+				if lastPage(c.start, cards, tc.available) {
+					break
+				}
+			}
+
+			if len(pages) != len(tc.pages) {
+				t.Fatalf("expected pages %v, got %v", tc.pages, pages)
+			}
+			for i := range pages {
+				if pages[i] != tc.pages[i] {
+					t.Fatalf("expected pages %v, got %v", tc.pages, pages)
+				}
+			}
+		})
+	}
+
+	// Linkedin serves no results past maxSearchResults, however many jobs it finds
+	if lastPage(maxSearchResults-2*pageSize, pageSize, 5000) {
+		t.Fatal("expected the second to last page to not be the last")
+	}
+	if !lastPage(maxSearchResults-pageSize, pageSize, 5000) {
+		t.Fatal("expected the page before maxSearchResults to be the last")
+	}
+}
@@ -0,0 +1,132 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/target"
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/ats"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+)
+
+// resolveApplyUrl looks up the company site of allowed jobs without Easy
+// Apply. Jobs on sites without a supported application form are filtered.
+func (l *Linkedin) resolveApplyUrl(ctx context.Context, post *datastore.JobPosting) error {
+	if !l.config.ExternalApply {
+		return nil
+	}
+
+	label, err := optionalText(ctx, applyButton)
+	if err != nil {
+		return fmt.Errorf("failed to get apply button. %w", err)
+	}
+	if strings.Contains(strings.ToLower(label), "easy apply") {
+		return nil
+	}
+
+	applyUrl, err := l.externalUrl(ctx)
+	if err != nil {
+		log.Warn().Err(err).Str("title", post.Title).Msg("Failed to open company site")
+		post.Status = datastore.StatusFiltered
+		post.FilterReason = "apply: company site not opened"
+		return nil
+	}
+
+	if ats.Detect(applyUrl) == nil {
+		host := applyUrl
+		if u, err := url.Parse(applyUrl); err == nil {
+			host = u.Hostname()
+		}
+
+		log.Debug().Str("title", post.Title).Str("url", applyUrl).Msg("Company site not supported")
+		post.Status = datastore.StatusFiltered
+		post.FilterReason = "apply: " + host + " not supported"
+		return nil
+	}
+
+	post.ApplyUrl = applyUrl
+	return nil
+}
+
+// externalUrl clicks the apply button of a job applied to on the company
+// site and returns the url of the tab it opens, closing the tab again.
+func (l *Linkedin) externalUrl(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	opened := cdp.WaitNewTarget(ctx, func(info *target.Info) bool {
+		return info.Type == "page"
+	})
+	if err := cdp.Run(ctx, cdp.Click(applyButton, cdp.ByQuery)); err != nil {
+		return "", fmt.Errorf("failed to click on apply button. %w", err)
+	}
+
+	var id target.ID
+	select {
+	case id = <-opened:
+	case <-ctx.Done():
+		return "", fmt.Errorf("failed to wait for company site. %w", ctx.Err())
+	}
+
+	// Cancelling the context of the tab closes it
+	tabCtx, cancel := cdp.NewContext(ctx, cdp.WithTargetID(id))
+	defer cancel()
+
+	var location string
+	if err := cdp.Run(tabCtx,
+		cdp.WaitReady(`body`, cdp.ByQuery),
+		cdp.Location(&location),
+	); err != nil {
+		return "", fmt.Errorf("failed to get company site url. %w", err)
+	}
+
+	return location, nil
+}
+
+// applyExternal applies for the job on the application form of the company
+// site it links to, with the answers and resume of the search that found it.
+func (l *Linkedin) applyExternal(ctx context.Context, post *datastore.JobPosting) (string, error) {
+	a := ats.Detect(post.ApplyUrl)
+	if a == nil {
+		log.Warn().Str("title", post.Title).Str("url", post.ApplyUrl).Msg("Company site not supported")
+		return datastore.StatusNeedsHuman, nil
+	}
+
+	applicant := ats.Applicant{
+		Answers:     l.settings(post).answers,
+		CoverLetter: l.config.CoverLetter,
+	}
+	if r := l.pickResume(post); r != nil {
+		applicant.Resume = r.Path
+	}
+
+	return a.Apply(ctx, l.ds, post, applicant)
+}
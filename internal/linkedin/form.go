@@ -0,0 +1,49 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/form"
+)
+
+const typeaheadOption = easyApplyModal + ` [role="listbox"] [role="option"]`
+
+// fillForm fills every empty question on the current page of the Easy Apply
+// modal from the answer bank of the search that found the job. Questions the
+// bank has no usable answer for are returned so the caller can decide what
+// to do with them.
+func (l *Linkedin) fillForm(ctx context.Context, post *datastore.JobPosting) ([]form.Field, error) {
+	filler := &form.Filler{
+		Bank: l.settings(post).answers,
+		Text: func(f *form.Field) (string, bool) {
+			return form.CoverLetterText(l.config.CoverLetter, post, f)
+		},
+		Suggestions: typeaheadOption,
+	}
+
+	return filler.Fill(ctx, easyApplyModal)
+}
@@ -35,84 +35,150 @@ import (
 
 	pcdp "github.com/chromedp/cdproto/cdp"
 	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/answers"
 	"github.com/k1ng440/job-bot/internal/config"
 	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/filter"
+	"github.com/k1ng440/job-bot/internal/platform"
 	"github.com/k1ng440/job-bot/internal/utils"
 	"github.com/rs/zerolog/log"
 )
 
-type regex struct {
-	title       []*regexp.Regexp
-	company     []*regexp.Regexp
-	description []*regexp.Regexp
-}
-
 type Linkedin struct {
-	ds     datastore.Datastore
-	regex  *regex
-	config config.Linkedin
+	ds      datastore.Datastore
+	resumes []resume
+	config  config.Linkedin
+	// global holds the settings of jobs found by no configured search
+	global   *jobSearch
+	searches []*jobSearch
+	// current is the search being crawled
+	current *jobSearch
+	// found is passed the eligible postings of the running search
+	found platform.Found
 }
 
 var (
-	ErrBlacklisted        = errors.New("blacklisted")
 	ErrSecurityCheck      = errors.New("security check")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	jobIdRegex            = regexp.MustCompile(`\/view\/([0-9]+)\/`)
+	postedSuffixRegex     = regexp.MustCompile(`(?i)\s*(reposted\s+)?(\d+|an?)\s+\w+\s+ago.*$`)
+	seniorityRegex        = regexp.MustCompile(`(?i)internship|entry level|associate|mid-senior level|director|executive`)
+	workplaceRegex        = regexp.MustCompile(`(?i)\b(remote|hybrid|on-site)\b`)
+	employmentRegex       = regexp.MustCompile(`(?i)full-time|part-time|contract|temporary|volunteer`)
+	applicantsRegex       = regexp.MustCompile(`(?i)([\d,]+)\s+applicants`)
+	salaryRegex           = regexp.MustCompile(`[$€£¥₹]\s*\d|\d\s*(€|EUR|USD|GBP)`)
 )
 
 const (
-	platform = "linkedin"
+	Name = "linkedin"
+
+	// pageSize is the number of job cards linkedin shows per search page
+	pageSize = 25
+	// maxSearchResults is the last offset linkedin serves results for
+	maxSearchResults = 1000
+	// maxScrolls caps the scrolling done to render the lazy loaded cards
+	maxScrolls = 20
+
+	searchResultsList = `.jobs-search-results-list`
+	jobCard           = searchResultsList + ` .job-card-container--clickable`
+	jobCardXPath      = `(//div[contains(@class, 'jobs-search-results-list')]//div[contains(@class, 'job-card-container--clickable')])[%d]`
 )
 
-func New(cfg config.Linkedin, ds datastore.Datastore) *Linkedin {
-	l := &Linkedin{
-		config: cfg,
-		ds:     ds,
-		regex: &regex{
-			title:       []*regexp.Regexp{},
-			company:     []*regexp.Regexp{},
-			description: []*regexp.Regexp{},
-		},
-	}
+func init() {
+	platform.Register(Name, func(cfg config.Config, bank *answers.Bank, ds datastore.Datastore) (platform.Platform, error) {
+		return New(cfg.Linkedin, bank, ds)
+	})
+}
 
-	// Compile regex patterns
-	// TODO: Handle errors here to provide better feedback
+func New(cfg config.Linkedin, bank *answers.Bank, ds datastore.Datastore) (*Linkedin, error) {
+	filters, err := filter.NewPostingFilter(cfg.PostingFilters)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, t := range cfg.Blacklists.Title {
-		l.regex.title = append(l.regex.title, regexp.MustCompile(t))
+	global := &jobSearch{filters: filters, answers: bank}
+	searches, err := newSearches(cfg, global)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, c := range cfg.Blacklists.Company {
-		l.regex.company = append(l.regex.company, regexp.MustCompile(c))
+	resumes, err := newResumes(cfg.Resumes)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, d := range cfg.Blacklists.Description {
-		l.regex.description = append(l.regex.description, regexp.MustCompile(d))
+	return &Linkedin{
+		config:   cfg,
+		ds:       ds,
+		resumes:  resumes,
+		global:   global,
+		searches: searches,
+		current:  global,
+	}, nil
+}
+
+// settings returns the search the job posting was found by, falling back
+// to the global settings for postings of searches no longer configured.
+func (l *Linkedin) settings(post *datastore.JobPosting) *jobSearch {
+	for _, s := range l.searches {
+		if s.name == post.Search {
+			return s
+		}
 	}
+	return l.global
+}
 
-	return l
+// Filters returns the posting filters of the search the job posting was
+// found by, which add the blacklists and languages of the search to the
+// global ones.
+func (l *Linkedin) Filters(post *datastore.JobPosting) *filter.PostingFilter {
+	return l.settings(post).filters
 }
 
-func (l *Linkedin) Run(ctx context.Context) error {
-	err := l.login(ctx)
-	if err != nil {
-		log.Error().Err(err).Msg("failed to login to linkedin")
-		return err
+func (l *Linkedin) Name() string {
+	return Name
+}
+
+func (l *Linkedin) Limits() platform.Limits {
+	return platform.Limits{
+		MaxApplications:           l.config.MaxApplications,
+		MaxApplicationsPerCompany: l.config.MaxApplicationsPerCompany,
 	}
+}
 
-	for _, url := range l.config.SearchUrls {
-		log.Info().Str("url", url).Msg("searching for jobs")
-		err = l.search(ctx, url)
-		if err != nil {
+// Search searches every search url and passes the eligible jobs to found
+// as they are stored.
+func (l *Linkedin) Search(ctx context.Context, found platform.Found) error {
+	l.found = found
+	defer func() { l.current = l.global }()
+
+	for _, s := range l.searches {
+		log.Info().Str("search", s.name).Str("url", s.url).Msg("searching for jobs")
+		l.current = s
+		if err := l.search(ctx, s.url); err != nil {
 			return err
 		}
 	}
 
-	time.Sleep(10 * time.Second)
 	return nil
 }
 
-func (l *Linkedin) login(ctx context.Context) error {
+// Apply applies for the open job with Easy Apply, or on the company site the
+// job links to, unless the daily limit of the search that found it is reached.
+func (l *Linkedin) Apply(ctx context.Context, post *datastore.JobPosting) (string, error) {
+	if err := l.checkSearchQuota(ctx, post); err != nil {
+		return datastore.StatusSkipped, err
+	}
+
+	if post.ApplyUrl != "" {
+		return l.applyExternal(ctx, post)
+	}
+
+	outcome, err := l.apply(ctx, post)
+	return string(outcome), err
+}
+
+func (l *Linkedin) Login(ctx context.Context) error {
 	var title string
 	if err := cdp.Run(ctx,
 		cdp.Navigate("https://www.linkedin.com/login"),
@@ -179,106 +245,174 @@ func (l *Linkedin) search(ctx context.Context, u string) error {
 		return fmt.Errorf("failed to parse url. %w", err)
 	}
 
-	start := 0
-
-	length, err := l.visitSearchPage(ctx, urlp, start)
+	c, err := l.resumeCrawl(ctx, u)
 	if err != nil {
 		return err
 	}
 
-	availableJobsCount, err := l.getAvailableJobs(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get available jobs. %w", err)
-	}
+	availableJobsCount := -1
+	for ; c.start < maxSearchResults; c.next() {
+		length, err := l.visitSearchPage(ctx, urlp, c.start)
+		if err != nil {
+			return err
+		}
 
-	// Iterate over all jobs
-	for start < availableJobsCount {
-		log.Info().Int("start", start).Int("available_jobs", availableJobsCount).Msg("iterating over jobs")
-		if start > 0 {
-			length, err = l.visitSearchPage(ctx, urlp, start)
+		if availableJobsCount < 0 {
+			availableJobsCount, err = l.getAvailableJobs(ctx)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to get available jobs. %w", err)
 			}
 		}
-		start += length + 1
+
+		log.Info().
+			Int("start", c.start).
+			Int("cards", length).
+			Int("available_jobs", availableJobsCount).
+			Msg("Iterating over jobs")
 
 		// Iterate over all jobs on the page
-		for i := 0; i < 7; i++ {
-			applied, err := l.haveApplied(ctx, i+1)
+		for i := 1; i <= length; i++ {
+			id, err := l.jobCardID(ctx, i)
 			if err != nil {
 				return err
 			}
 
-			if applied {
-				log.Debug().Msg("Already applied for job")
+			if id != "" && c.visited(id) {
+				log.Debug().Str("id", id).Msg("Job visited before the checkpoint. Skipping")
 				continue
 			}
 
-			if err := cdp.Run(ctx,
-				cdp.Click(fmt.Sprintf(`(//a[contains(@class, 'job-card-list__title')])[%d]`, i+1)),
-			); err != nil {
-				return fmt.Errorf("failed to click on button. %w", err)
-			}
-
-			log.Debug().Msg("Clicked on job")
-
-			if err := cdp.Run(ctx, cdp.WaitEnabled(`button.jobs-apply-button`, cdp.ByQuery)); err != nil {
-				return fmt.Errorf("failed to wait for button. %w", err)
+			if err := l.visitJobCard(ctx, i); err != nil {
+				return err
 			}
 
-			log.Debug().Msg("Job details page loaded")
-
-			// Get job details
-			// TODO: Save job details in a file
-			post, err := l.parseJobDescription(ctx)
-			if err != nil {
-				if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-					continue
+			if id != "" {
+				c.seen = append(c.seen, id)
+				if err := l.saveCrawl(ctx, c); err != nil {
+					return err
 				}
-				return err
-			}
-			if post == nil {
-				continue
 			}
+		}
 
-			if err := l.apply(ctx, post); err != nil {
-				log.Warn().Str("title", post.Title).Msg("Failed to apply for job")
-			}
+		if lastPage(c.start, length, availableJobsCount) {
+			break
 		}
 	}
 
-	return nil
+	return l.finishCrawl(ctx, c)
 }
 
-func (l *Linkedin) apply(ctx context.Context, post *datastore.JobPosting) error {
-	log.Info().Str("title", post.Title).Msg("Applying for job")
+// visitJobCard opens the job card at the 1-based index of the current
+// search page, stores the posting and passes it on when eligible.
+func (l *Linkedin) visitJobCard(ctx context.Context, index int) error {
+	applied, err := l.haveApplied(ctx, index)
+	if err != nil {
+		return err
+	}
+
+	if applied {
+		log.Debug().Msg("Already applied for job")
+		return nil
+	}
 
 	if err := cdp.Run(ctx,
-		cdp.Click(`button.jobs-apply-button`, cdp.ByQuery),
-		cdp.Click(`button.jdfsaobs-apply-button`, cdp.ByQuery),
+		cdp.Click(fmt.Sprintf(jobCardXPath+`//a[contains(@class, 'job-card-list__title')]`, index)),
 	); err != nil {
 		return fmt.Errorf("failed to click on button. %w", err)
 	}
 
-	log.Debug().Msg("Clicked on apply button")
+	log.Debug().Msg("Clicked on job")
 
-	return nil
+	if err := cdp.Run(ctx, cdp.WaitEnabled(`button.jobs-apply-button`, cdp.ByQuery)); err != nil {
+		return fmt.Errorf("failed to wait for button. %w", err)
+	}
+
+	log.Debug().Msg("Job details page loaded")
+
+	// Get job details
+	post, err := l.parseJobDescription(ctx)
+	if err != nil {
+		if errors.Is(err, datastore.ErrAlreadyExists) {
+			return nil
+		}
+		return err
+	}
+	if post == nil {
+		return nil
+	}
+
+	return l.found(ctx, post)
 }
 
-func (l *Linkedin) visitSearchPage(ctx context.Context, u *url.URL, start int) (int, error) {
-	length := 0
-	container := `.jobs-search-results-list .job-card-container--clickable`
+// FetchDetails navigates to the job page and waits for the apply button.
+// Jobs no longer accepting applications have none, so it gives up after a while.
+func (l *Linkedin) FetchDetails(ctx context.Context, post *datastore.JobPosting) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
+	return cdp.Run(ctx,
+		cdp.Navigate(l.jobUrl(post.ID)),
+		cdp.WaitEnabled(applyButton, cdp.ByQuery),
+	)
+}
+
+// visitSearchPage opens the search page at the offset and returns the number
+// of job cards on it.
+func (l *Linkedin) visitSearchPage(ctx context.Context, u *url.URL, start int) (int, error) {
 	if err := cdp.Run(ctx,
 		cdp.Navigate(l.listUrl(u, start)),
-		cdp.WaitVisible(container, cdp.ByQuery),
-		cdp.Sleep(1*time.Second),
-		cdp.Evaluate(`document.querySelectorAll('`+container+`').length`, &length),
+		cdp.WaitVisible(searchResultsList, cdp.ByQuery),
 	); err != nil {
 		return 0, fmt.Errorf("failed to navigate to search page. %w", err)
 	}
 
-	return length, nil
+	return l.loadAllCards(ctx)
+}
+
+// loadAllCardsJS scrolls the results list by one screen and reports how many
+// cards are rendered and whether the end of the list was reached.
+const loadAllCardsJS = `(() => {
+	const list = document.querySelector('` + searchResultsList + `');
+	if (list) {
+		list.scrollBy(0, list.clientHeight);
+	}
+	return {
+		count: document.querySelectorAll('` + jobCard + `').length,
+		bottom: !list || list.scrollTop + list.clientHeight >= list.scrollHeight - 1,
+	};
+})()`
+
+// loadAllCards scrolls the results list until linkedin has rendered every
+// lazy loaded job card, and returns the number of cards.
+func (l *Linkedin) loadAllCards(ctx context.Context) (int, error) {
+	var state struct {
+		Count  int  `json:"count"`
+		Bottom bool `json:"bottom"`
+	}
+
+	last := -1
+	for i := 0; i < maxScrolls; i++ {
+		if err := cdp.Run(ctx,
+			cdp.Evaluate(loadAllCardsJS, &state),
+			cdp.Sleep(500*time.Millisecond),
+		); err != nil {
+			return 0, fmt.Errorf("failed to scroll search results. %w", err)
+		}
+
+		if state.Bottom && state.Count == last {
+			break
+		}
+		last = state.Count
+	}
+
+	// Go back to the top so the first card is in view
+	if err := cdp.Run(ctx,
+		cdp.Evaluate(`document.querySelector('`+searchResultsList+`').scrollTo(0, 0)`, nil),
+	); err != nil {
+		return 0, fmt.Errorf("failed to scroll search results. %w", err)
+	}
+
+	return state.Count, nil
 }
 
 func (l *Linkedin) getAvailableJobs(ctx context.Context) (int, error) {
@@ -300,8 +434,7 @@ func (l *Linkedin) getAvailableJobs(ctx context.Context) (int, error) {
 func (l *Linkedin) haveApplied(ctx context.Context, index int) (bool, error) {
 	// Ignore already applied jobs
 	var applied string
-	selector := fmt.Sprintf(`(//div[contains(@class, 'jobs-search-results-list')])[%d]`+
-		`//span[contains(@class, 'tvm__text--neutral')]`, index)
+	selector := fmt.Sprintf(jobCardXPath+`//span[contains(@class, 'tvm__text--neutral')]`, index)
 	if err := cdp.Run(ctx,
 		cdp.Text(selector, &applied, cdp.AtLeast(0)),
 	); err != nil {
@@ -320,98 +453,175 @@ func (l *Linkedin) haveApplied(ctx context.Context, index int) (bool, error) {
 }
 
 func (l *Linkedin) parseJobDescription(ctx context.Context) (*datastore.JobPosting, error) {
-	var link []*pcdp.Node
-	var title, company, description string
-
-	if err := cdp.Run(ctx,
-		cdp.Nodes(`(//div[contains(@class, 'jobs-unified-top-card__content--two-pane')]//a)[1]`, &link, cdp.AtLeast(0)),
-		cdp.Text(`(//div[contains(@class, 'jobs-unified-top-card__content--two-pane')]//a)[1]/h2`, &title, cdp.AtLeast(0)),
-		cdp.Text(`(//div[contains(@class, 'jobs-unified-top-card__content--two-pane')]//a)[2]`, &company, cdp.AtLeast(0)),
-		cdp.Text(`//div[contains(@class, 'jobs-description-content__text')]/span`, &description, cdp.AtLeast(0)),
-	); err != nil {
+	post, err := ScrapeJobDetails(ctx)
+	if err != nil {
 		log.Error().Err(err).Msg("failed to get job details")
-		return nil, fmt.Errorf("failed to get job details. %w", err)
+		return nil, err
 	}
 	log.Debug().Msg("Job details fetched")
 
-	post := &datastore.JobPosting{
-		Platform: platform,
-		Url:      link[0].AttributeValue("href"),
-		ID:       jobIdRegex.FindStringSubmatch(link[0].AttributeValue("href"))[1],
-		Company:  company,
-		Title:    title,
-	}
+	post.Search = l.current.name
 
-	err := l.filterPosting(ctx, post, description)
-	if err != nil {
-		if errors.Is(err, ErrBlacklisted) {
-			return nil, nil
-		}
+	// Rejected postings are stored as well so the reason can be audited
+	result := l.current.filters.Evaluate(post)
+	if !result.Allowed {
+		log.Debug().Str("title", post.Title).Str("reason", result.Reason()).Msg("Job blacklisted")
+		post.Status = datastore.StatusFiltered
+		post.FilterReason = result.Reason()
+	} else {
+		log.Debug().Str("title", post.Title).Msg("Job allowed")
+		post.Status = datastore.StatusQueued
 
-		return nil, err
+		if err := l.resolveApplyUrl(ctx, post); err != nil {
+			return nil, err
+		}
 	}
 
 	err = l.ds.InsertJobPosting(ctx, post)
+	if errors.Is(err, datastore.ErrAlreadyExists) {
+		log.Debug().Str("id", post.ID).Msg("Job posting already stored")
+		return nil, err
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("failed to insert job posting")
 		return nil, fmt.Errorf("failed to insert job posting. %w", err)
 	}
 
+	if post.Status == datastore.StatusFiltered {
+		return nil, nil
+	}
+
 	return post, nil
 }
 
-func (l *Linkedin) filterPosting(ctx context.Context, post *datastore.JobPosting, description string) error {
-	// Check if the job title matches the regex pattern
-	// If doesn't matches then check if the description contains the required languages
-	titleBlacklisted := false
-	for _, titleRegex := range l.regex.title {
-		if titleRegex.MatchString(post.Title) {
-			titleBlacklisted = true
-			break
-		}
+// ScrapeJobDetails reads the job posting shown in the job details pane of the
+// current page.
+func ScrapeJobDetails(ctx context.Context) (*datastore.JobPosting, error) {
+	var link []*pcdp.Node
+	var title, company, description string
+
+	if err := cdp.Run(ctx,
+		cdp.Nodes(`(//div[contains(@class, 'jobs-unified-top-card__content--two-pane')]//a)[1]`, &link, cdp.AtLeast(0)),
+		cdp.Text(`(//div[contains(@class, 'jobs-unified-top-card__content--two-pane')]//a)[1]/h2`, &title, cdp.AtLeast(0)),
+		cdp.Text(`(//div[contains(@class, 'jobs-unified-top-card__content--two-pane')]//a)[2]`, &company, cdp.AtLeast(0)),
+		cdp.Text(`//div[contains(@class, 'jobs-description-content__text')]/span`, &description, cdp.AtLeast(0)),
+	); err != nil {
+		return nil, fmt.Errorf("failed to get job details. %w", err)
 	}
 
-	descriptionBlacklisted := false
-	for _, descRegex := range l.regex.description {
-		if descRegex.MatchString(description) {
-			descriptionBlacklisted = true
-			break
+	if len(link) == 0 {
+		return nil, errors.New("failed to get job details. job link not found")
+	}
+
+	href := link[0].AttributeValue("href")
+	id := jobIdRegex.FindStringSubmatch(href)
+	if id == nil {
+		return nil, fmt.Errorf("failed to get job id from %q", href)
+	}
+
+	// The optional parts of the details pane
+	var primary, insight, hiringTeam string
+	for _, t := range []struct {
+		sel  string
+		text *string
+	}{
+		{`div.jobs-unified-top-card__primary-description`, &primary},
+		{`li.jobs-unified-top-card__job-insight`, &insight},
+		{`div.hirer-card__hirer-information strong`, &hiringTeam},
+	} {
+		text, err := optionalText(ctx, t.sel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job details. %w", err)
 		}
+		*t.text = text
 	}
 
-	// detect language
-	lang := utils.DetectLanguage(description)
-	if lang == "" {
-		log.Warn().Msg("Failed to detect language")
+	post := &datastore.JobPosting{
+		Platform:       Name,
+		Url:            href,
+		ID:             id[1],
+		Company:        strings.TrimSpace(company),
+		Title:          strings.TrimSpace(title),
+		Location:       parseLocation(primary),
+		Seniority:      strings.ToLower(seniorityRegex.FindString(insight)),
+		WorkplaceType:  strings.ToLower(workplaceRegex.FindString(insight + " " + primary)),
+		EmploymentType: strings.ToLower(employmentRegex.FindString(insight)),
+		Salary:         parseSalary(insight),
+		HiringTeam:     hiringTeam,
+		Description:    strings.TrimSpace(description),
 	}
 
-	// Default to english
-	if len(l.config.Languages) == 0 {
-		l.config.Languages = []string{"english"}
+	if m := applicantsRegex.FindStringSubmatch(primary); m != nil {
+		if count, err := utils.ParseStringToInt(m[1]); err == nil {
+			post.ApplicantCount = count
+		}
 	}
 
-	// Check if the language is allowed
-	allowedLang := false
-	for _, allowed := range l.config.Languages {
-		if lang == strings.ToLower(allowed) {
-			allowedLang = true
-			break
+	if postedAt, ok := utils.ParsePostedAgo(primary, time.Now()); ok {
+		post.PostedAt = postedAt
+	} else {
+		log.Debug().Str("title", title).Msg("Failed to parse posted date")
+	}
+
+	return post, nil
+}
+
+// parseSalary returns the part of the job insight that shows a salary,
+// which reads like "$120K/yr - $150K/yr · Remote · Full-time".
+func parseSalary(insight string) string {
+	for _, part := range strings.Split(insight, "·") {
+		if salaryRegex.MatchString(part) {
+			return strings.TrimSpace(part)
 		}
 	}
+	return ""
+}
 
-	if titleBlacklisted || descriptionBlacklisted || !allowedLang {
-		log.Debug().Str("title", post.Title).Msg("Job blacklisted")
-		return ErrBlacklisted
+// parseLocation takes the location out of the top card description,
+// which reads like "Acme · Berlin, Germany (Remote) 2 days ago · 42 applicants".
+func parseLocation(primary string) string {
+	parts := strings.Split(primary, "·")
+	if len(parts) < 2 {
+		return ""
 	}
 
-	log.Debug().Str("title", post.Title).Msg("Job allowed")
-	return nil
+	return strings.TrimSpace(postedSuffixRegex.ReplaceAllString(parts[1], ""))
 }
 
 func (l *Linkedin) listUrl(u *url.URL, start int) string {
 	query := u.Query()
 	query.Set("start", strconv.Itoa(start))
-	query.Set("f_AL", "true")
+	if !l.config.ExternalApply {
+		query.Set("f_AL", "true")
+	}
+
+	// Let linkedin drop stale postings unless the url asks for a time range
+	if l.config.SearchMaxAge && l.config.MaxAgeDays > 0 && query.Get("f_TPR") == "" {
+		query.Set("f_TPR", "r"+strconv.Itoa(l.config.MaxAgeDays*24*60*60))
+	}
 	u.RawQuery = query.Encode()
 	return u.String()
 }
+
+func (l *Linkedin) jobUrl(id string) string {
+	return "https://www.linkedin.com/jobs/view/" + id + "/"
+}
+
+// optionalText returns the text of the first node matching the selector,
+// or an empty string when nothing matches.
+func optionalText(ctx context.Context, sel string) (string, error) {
+	var nodes []*pcdp.Node
+	if err := cdp.Run(ctx, cdp.Nodes(sel, &nodes, cdp.ByQuery, cdp.AtLeast(0))); err != nil {
+		return "", err
+	}
+	if len(nodes) == 0 {
+		return "", nil
+	}
+
+	var text string
+	if err := cdp.Run(ctx, cdp.Text(sel, &text, cdp.ByQuery)); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(text), nil
+}
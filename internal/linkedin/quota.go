@@ -0,0 +1,52 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/platform"
+)
+
+// checkSearchQuota returns platform.ErrSearchLimitReached once the daily limit
+// of the search that found the posting is reached. The daily and company
+// limits are checked by the platform runner.
+func (l *Linkedin) checkSearchQuota(ctx context.Context, post *datastore.JobPosting) error {
+	s := l.settings(post)
+	if s.maxApplications <= 0 {
+		return nil
+	}
+
+	count, err := l.ds.GetAppliedTodayCountBySearch(ctx, post.Platform, post.Search)
+	if err != nil {
+		return fmt.Errorf("failed to get applied count by search. %w", err)
+	}
+	if count >= s.maxApplications {
+		return platform.ErrSearchLimitReached
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/platform"
+)
+
+func TestCheckSearchQuota(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.NewMemoryDatastore()
+	l, err := New(config.Linkedin{
+		Searches: []config.Search{{Name: "Go Berlin", Keywords: "golang", MaxApplications: 1}},
+	}, nil, ds)
+	if err != nil {
+		t.Fatalf("failed to create linkedin bot: %v", err)
+	}
+
+	submitted := &datastore.JobPosting{Platform: Name, ID: "1", Search: "Go Berlin"}
+	if err := ds.InsertJobPosting(ctx, submitted); err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+	for _, status := range []string{datastore.StatusApplying, datastore.StatusSubmitted} {
+		if err := ds.SetJobPostingStatus(ctx, Name, "1", status); err != nil {
+			t.Fatalf("failed to set job posting status: %v", err)
+		}
+	}
+
+	post := &datastore.JobPosting{Platform: Name, ID: "2", Search: "Go Berlin"}
+	if err := l.checkSearchQuota(ctx, post); !errors.Is(err, platform.ErrSearchLimitReached) {
+		t.Fatalf("expected ErrSearchLimitReached, got %v", err)
+	}
+
+	// Jobs found by other searches are not limited
+	post.Search = ""
+	if err := l.checkSearchQuota(ctx, post); err != nil {
+		t.Fatalf("expected the job to be allowed, got %v", err)
+	}
+}
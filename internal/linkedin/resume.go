@@ -0,0 +1,223 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	cdp "github.com/chromedp/chromedp"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/k1ng440/job-bot/internal/form"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	resumeUpload      = easyApplyModal + ` input[type="file"][id*="upload-resume"]`
+	coverLetterUpload = easyApplyModal + ` input[type="file"][id*="cover-letter"]`
+)
+
+type resume struct {
+	config.Resume
+	title []*regexp.Regexp
+}
+
+// findResumeJS looks up the resume card with the given file name and returns
+// a selector to click it, or whether it is already selected.
+const findResumeJS = `((name) => {
+	const cards = document.querySelectorAll('` + easyApplyModal + ` .jobs-document-upload-redesign-card__container');
+	for (const card of cards) {
+		const header = card.querySelector('h3');
+		if (!header || header.innerText.trim() !== name) continue;
+
+		window.__jbField = window.__jbField || 0;
+		if (!card.dataset.jbField) card.dataset.jbField = String(window.__jbField++);
+		return {
+			found: true,
+			selected: card.className.includes('--selected'),
+			selector: '[data-jb-field="' + card.dataset.jbField + '"]',
+		};
+	}
+	return {found: false, selected: false, selector: ''};
+})`
+
+type resumeCard struct {
+	Found    bool   `json:"found"`
+	Selected bool   `json:"selected"`
+	Selector string `json:"selector"`
+}
+
+func newResumes(cfg []config.Resume) ([]resume, error) {
+	resumes := make([]resume, 0, len(cfg))
+	for _, r := range cfg {
+		r.Name = resumeName(r)
+
+		title := make([]*regexp.Regexp, 0, len(r.Title))
+		for _, t := range r.Title {
+			re, err := regexp.Compile(t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid title pattern %q of resume %q. %w", t, r.Name, err)
+			}
+			title = append(title, re)
+		}
+
+		resumes = append(resumes, resume{Resume: r, title: title})
+	}
+
+	return resumes, nil
+}
+
+// resumeName returns the name linkedin shows for the resume, which is its
+// file name unless configured.
+func resumeName(r config.Resume) string {
+	if r.Name == "" {
+		return filepath.Base(r.Path)
+	}
+	return r.Name
+}
+
+// hasResume reports whether one of the resumes is called name.
+func hasResume(cfg []config.Resume, name string) bool {
+	for _, r := range cfg {
+		if resumeName(r) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pickResume returns the resume to send for the job posting, or nil if no
+// resume is configured. The resume chosen by the search of the posting wins.
+func (l *Linkedin) pickResume(post *datastore.JobPosting) *resume {
+	if len(l.resumes) == 0 {
+		return nil
+	}
+
+	if name := l.settings(post).resume; name != "" {
+		for i := range l.resumes {
+			if l.resumes[i].Name == name {
+				return &l.resumes[i]
+			}
+		}
+	}
+
+	for i := range l.resumes {
+		for _, t := range l.resumes[i].title {
+			if t.MatchString(post.Title) {
+				return &l.resumes[i]
+			}
+		}
+	}
+
+	for i := range l.resumes {
+		if l.resumes[i].Default {
+			return &l.resumes[i]
+		}
+	}
+
+	return &l.resumes[0]
+}
+
+// selectResume picks the resume for the job on the resume step of the modal,
+// uploading it first when linkedin does not know it yet. Pages without a
+// resume section are left alone.
+func (l *Linkedin) selectResume(ctx context.Context, post *datastore.JobPosting) error {
+	r := l.pickResume(post)
+	if r == nil {
+		return nil
+	}
+
+	var card resumeCard
+	if err := cdp.Run(ctx, cdp.Evaluate(form.CallJS(findResumeJS, r.Name), &card)); err != nil {
+		return fmt.Errorf("failed to look up resume. %w", err)
+	}
+
+	if !card.Found {
+		upload, err := l.exists(ctx, resumeUpload)
+		if err != nil {
+			return err
+		}
+		if !upload {
+			return nil
+		}
+		if r.Path == "" {
+			log.Warn().Str("resume", r.Name).Msg("Resume is not uploaded and has no path")
+			return nil
+		}
+
+		log.Info().Str("resume", r.Name).Msg("Uploading resume")
+		if err := l.upload(ctx, resumeUpload, r.Path); err != nil {
+			return fmt.Errorf("failed to upload resume. %w", err)
+		}
+
+		// The uploaded resume is usually selected right away
+		if err := cdp.Run(ctx, cdp.Evaluate(form.CallJS(findResumeJS, r.Name), &card)); err != nil {
+			return fmt.Errorf("failed to look up resume. %w", err)
+		}
+	}
+
+	if card.Found && !card.Selected {
+		log.Debug().Str("resume", r.Name).Msg("Selecting resume")
+		if err := cdp.Run(ctx, cdp.Click(card.Selector, cdp.ByQuery)); err != nil {
+			return fmt.Errorf("failed to select resume. %w", err)
+		}
+	}
+
+	return nil
+}
+
+// uploadCoverLetter uploads the cover letter file when the page asks for one.
+func (l *Linkedin) uploadCoverLetter(ctx context.Context, post *datastore.JobPosting) error {
+	if l.config.CoverLetter.Path == "" {
+		return nil
+	}
+
+	upload, err := l.exists(ctx, coverLetterUpload)
+	if err != nil || !upload {
+		return err
+	}
+
+	log.Info().Str("title", post.Title).Msg("Uploading cover letter")
+	if err := l.upload(ctx, coverLetterUpload, l.config.CoverLetter.Path); err != nil {
+		return fmt.Errorf("failed to upload cover letter. %w", err)
+	}
+
+	return nil
+}
+
+func (l *Linkedin) upload(ctx context.Context, sel, path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	return cdp.Run(ctx,
+		cdp.SetUploadFiles(sel, []string{path}, cdp.ByQuery),
+		cdp.Sleep(2*time.Second),
+	)
+}
@@ -0,0 +1,99 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+func TestPickResume(t *testing.T) {
+	resumes := []config.Resume{
+		{Path: "/resumes/backend.pdf"},
+		{Path: "/resumes/sre.pdf", Title: []string{`(?i)reliability|devops`}},
+		{Name: "General", Path: "/resumes/general.pdf", Default: true},
+	}
+	searches := []config.Search{{Name: "SRE", Keywords: "sre", Resume: "backend.pdf"}}
+
+	for _, tc := range []struct {
+		name     string
+		resumes  []config.Resume
+		post     datastore.JobPosting
+		expected string
+	}{
+		{"search resume wins over title", resumes, datastore.JobPosting{Search: "SRE", Title: "DevOps Engineer"}, "backend.pdf"},
+		{"title pattern", resumes, datastore.JobPosting{Search: "removed search", Title: "Site Reliability Engineer"}, "sre.pdf"},
+		{"default", resumes, datastore.JobPosting{Title: "Golang Engineer"}, "General"},
+		{"first without a default", resumes[:2], datastore.JobPosting{Title: "Golang Engineer"}, "backend.pdf"},
+		{"none configured", nil, datastore.JobPosting{Title: "Golang Engineer"}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.Linkedin{Resumes: tc.resumes}
+			if len(tc.resumes) > 0 {
+				cfg.Searches = searches
+			}
+
+			l, err := New(cfg, nil, datastore.NewMemoryDatastore())
+			if err != nil {
+				t.Fatalf("failed to create linkedin bot: %v", err)
+			}
+
+			name := ""
+			if r := l.pickResume(&tc.post); r != nil {
+				name = r.Name
+			}
+			if name != tc.expected {
+				t.Fatalf("expected resume %q, got %q", tc.expected, name)
+			}
+		})
+	}
+
+	if _, err := New(config.Linkedin{Resumes: []config.Resume{{Path: "/resumes/sre.pdf", Title: []string{`(?i)sre(`}}}}, nil, nil); err == nil {
+		t.Fatal("expected an invalid title pattern to fail")
+	}
+}
+
+func TestHasResume(t *testing.T) {
+	resumes := []config.Resume{
+		{Path: "/resumes/backend.pdf"},
+		{Name: "General", Path: "/resumes/general.pdf"},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		expected bool
+	}{
+		{"backend.pdf", true},
+		{"General", true},
+		{"general.pdf", false},
+		{"/resumes/backend.pdf", false},
+		{"", false},
+	} {
+		if got := hasResume(resumes, tc.name); got != tc.expected {
+			t.Fatalf("expected hasResume(%q) to be %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
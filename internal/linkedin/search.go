@@ -0,0 +1,243 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/filter"
+)
+
+const searchBaseUrl = "https://www.linkedin.com/jobs/search/"
+
+// The values of the search url filters, keyed by the normalized config value.
+var (
+	workplaceFilters = map[string]string{
+		"onsite": "1",
+		"remote": "2",
+		"hybrid": "3",
+	}
+	experienceFilters = map[string]string{
+		"internship": "1",
+		"entry":      "2",
+		"associate":  "3",
+		"midsenior":  "4",
+		"director":   "5",
+		"executive":  "6",
+	}
+	jobTypeFilters = map[string]string{
+		"fulltime":   "F",
+		"parttime":   "P",
+		"contract":   "C",
+		"temporary":  "T",
+		"volunteer":  "V",
+		"internship": "I",
+		"other":      "O",
+	}
+	datePostedFilters = map[string]string{
+		"any":   "",
+		"month": "r2592000",
+		"week":  "r604800",
+		"day":   "r86400",
+	}
+	sortByFilters = map[string]string{
+		"relevant": "R",
+		"recent":   "DD",
+	}
+)
+
+// jobSearch is a search url to crawl and the settings of the jobs it finds.
+type jobSearch struct {
+	name    string
+	url     string
+	filters *filter.PostingFilter
+	answers *answers.Bank
+	// resume is the name of the resume to send, empty to pick one by job title
+	resume string
+	// maxApplications is the daily limit of the search, 0 for none
+	maxApplications int
+}
+
+// newSearches returns the configured searches followed by the raw search
+// urls, which use the global settings.
+func newSearches(cfg config.Linkedin, global *jobSearch) ([]*jobSearch, error) {
+	searches := make([]*jobSearch, 0, len(cfg.Searches)+len(cfg.SearchUrls))
+	names := map[string]bool{}
+	for i, s := range cfg.Searches {
+		js, err := newSearch(cfg, s, global)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search %d. %w", i+1, err)
+		}
+		if names[js.name] {
+			return nil, fmt.Errorf("invalid search %d. duplicate name %q", i+1, js.name)
+		}
+		names[js.name] = true
+		searches = append(searches, js)
+	}
+
+	for _, u := range cfg.SearchUrls {
+		searches = append(searches, &jobSearch{
+			name:    u,
+			url:     u,
+			filters: global.filters,
+			answers: global.answers,
+		})
+	}
+
+	return searches, nil
+}
+
+// newSearch builds the search url of a search and merges its settings on
+// top of the global ones.
+func newSearch(cfg config.Linkedin, s config.Search, global *jobSearch) (*jobSearch, error) {
+	u, err := searchUrl(s)
+	if err != nil {
+		return nil, err
+	}
+
+	js := &jobSearch{
+		name:            s.Name,
+		url:             u,
+		filters:         global.filters,
+		answers:         global.answers,
+		resume:          s.Resume,
+		maxApplications: s.MaxApplications,
+	}
+	if js.name == "" {
+		js.name = u
+	}
+
+	if s.Resume != "" && !hasResume(cfg.Resumes, s.Resume) {
+		return nil, fmt.Errorf("unknown resume %q", s.Resume)
+	}
+
+	if len(s.Answers) > 0 {
+		js.answers, err = global.answers.With(s.Answers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	blacklists := len(s.Blacklists.Title) + len(s.Blacklists.Company) + len(s.Blacklists.Description)
+	if len(s.Languages) > 0 || blacklists > 0 {
+		cfg.Blacklists = config.Patterns{
+			Title:       concat(cfg.Blacklists.Title, s.Blacklists.Title),
+			Company:     concat(cfg.Blacklists.Company, s.Blacklists.Company),
+			Description: concat(cfg.Blacklists.Description, s.Blacklists.Description),
+		}
+		if len(s.Languages) > 0 {
+			cfg.Languages = s.Languages
+		}
+
+		js.filters, err = filter.NewPostingFilter(cfg.PostingFilters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return js, nil
+}
+
+func concat(a, b []string) []string {
+	return append(append([]string{}, a...), b...)
+}
+
+// searchUrl builds the linkedin search url of a search. Paging and the easy
+// apply filter are added by listUrl.
+func searchUrl(s config.Search) (string, error) {
+	query := url.Values{}
+	if s.Keywords != "" {
+		query.Set("keywords", s.Keywords)
+	}
+	if s.Location != "" {
+		query.Set("location", s.Location)
+	}
+	if s.GeoID != "" {
+		query.Set("geoId", s.GeoID)
+	}
+	if s.Distance > 0 {
+		query.Set("distance", strconv.Itoa(s.Distance))
+	}
+
+	for _, f := range []struct {
+		param   string
+		name    string
+		values  []string
+		filters map[string]string
+	}{
+		{"f_WT", "workplace type", s.Workplace, workplaceFilters},
+		{"f_E", "experience level", s.Experience, experienceFilters},
+		{"f_JT", "job type", s.JobTypes, jobTypeFilters},
+	} {
+		values, err := filterValues(f.name, f.values, f.filters)
+		if err != nil {
+			return "", err
+		}
+		if len(values) > 0 {
+			query.Set(f.param, strings.Join(values, ","))
+		}
+	}
+
+	if s.DatePosted != "" {
+		values, err := filterValues("date posted", []string{s.DatePosted}, datePostedFilters)
+		if err != nil {
+			return "", err
+		}
+		if values[0] != "" {
+			query.Set("f_TPR", values[0])
+		}
+	}
+
+	if s.SortBy != "" {
+		values, err := filterValues("sort order", []string{s.SortBy}, sortByFilters)
+		if err != nil {
+			return "", err
+		}
+		query.Set("sortBy", values[0])
+	}
+
+	return searchBaseUrl + "?" + query.Encode(), nil
+}
+
+// filterValues maps config values to search url values. Values are matched
+// ignoring case, spaces, dashes and underscores, so "Full-time" and
+// "full time" are the same.
+func filterValues(name string, values []string, filters map[string]string) ([]string, error) {
+	var mapped []string
+	for _, v := range values {
+		key := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(v))
+		f, ok := filters[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s %q", name, v)
+		}
+		mapped = append(mapped, f)
+	}
+
+	return mapped, nil
+}
@@ -0,0 +1,196 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package linkedin
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+func TestSearchUrl(t *testing.T) {
+	u, err := searchUrl(config.Search{
+		Keywords:   "golang developer",
+		Location:   "Berlin, Germany",
+		GeoID:      "103035651",
+		Workplace:  []string{"Remote", "hybrid"},
+		Experience: []string{"mid-senior", "Director"},
+		JobTypes:   []string{"full time", "contract"},
+		DatePosted: "week",
+		SortBy:     "recent",
+		Distance:   25,
+	})
+	if err != nil {
+		t.Fatalf("failed to build search url: %v", err)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("failed to parse search url %q: %v", u, err)
+	}
+	if parsed.Host != "www.linkedin.com" || parsed.Path != "/jobs/search/" {
+		t.Fatalf("unexpected search url %q", u)
+	}
+
+	want := map[string]string{
+		"keywords": "golang developer",
+		"location": "Berlin, Germany",
+		"geoId":    "103035651",
+		"f_WT":     "2,3",
+		"f_E":      "4,5",
+		"f_JT":     "F,C",
+		"f_TPR":    "r604800",
+		"sortBy":   "DD",
+		"distance": "25",
+	}
+	query := parsed.Query()
+	for param, value := range want {
+		if got := query.Get(param); got != value {
+			t.Fatalf("expected %s=%q, got %q", param, value, got)
+		}
+	}
+	if len(query) != len(want) {
+		t.Fatalf("unexpected parameters in %q", u)
+	}
+
+	if _, err := searchUrl(config.Search{Workplace: []string{"moon"}}); err == nil {
+		t.Fatal("expected an unknown workplace type to fail")
+	}
+}
+
+func TestSearchOverrides(t *testing.T) {
+	bank, err := answers.New([]config.Answer{{Question: `(?i)notice period`, Answer: "3 months"}})
+	if err != nil {
+		t.Fatalf("failed to create answer bank: %v", err)
+	}
+
+	l, err := New(config.Linkedin{
+		PostingFilters: config.PostingFilters{
+			Blacklists: config.Patterns{Title: []string{`(?i)senior`}},
+			// Rejects what gets past the blacklists before the slow language detection
+			Salary: config.Salary{MinAnnual: 1, Missing: config.SalaryMissingReject},
+		},
+		Resumes: []config.Resume{
+			{Path: "/resumes/backend.pdf", Default: true},
+			{Path: "/resumes/sre.pdf"},
+		},
+		Searches: []config.Search{{
+			Name:       "SRE remote",
+			Keywords:   "sre",
+			Blacklists: config.Patterns{Company: []string{`(?i)acme`}},
+			Resume:     "sre.pdf",
+			Answers:    []config.Answer{{Question: `(?i)notice period`, Answer: "1 month"}},
+		}},
+		SearchUrls: []string{searchBaseUrl + "?keywords=golang"},
+	}, bank, datastore.NewMemoryDatastore())
+	if err != nil {
+		t.Fatalf("failed to create linkedin bot: %v", err)
+	}
+	if len(l.searches) != 2 || l.searches[1].name != l.searches[1].url {
+		t.Fatalf("expected the search and the search url, got %+v", l.searches)
+	}
+
+	description := "We are looking for an engineer who enjoys keeping our systems running and helping the team ship reliable software."
+	acme := &datastore.JobPosting{Title: "Site Reliability Engineer", Company: "Acme", Description: description}
+	senior := &datastore.JobPosting{Title: "Senior Site Reliability Engineer", Company: "Other", Description: description}
+
+	// The search adds to the global blacklists
+	search := l.searches[0]
+	if res := search.filters.Evaluate(acme); res.Allowed || res.Rule != "company" {
+		t.Fatalf("expected the search to reject the company, got %+v", res)
+	}
+	if res := search.filters.Evaluate(senior); res.Allowed || res.Rule != "title" {
+		t.Fatalf("expected the search to keep the global blacklist, got %+v", res)
+	}
+	if res := l.global.filters.Evaluate(acme); res.Rule != "salary" {
+		t.Fatalf("expected the search blacklist to leave the global filters alone, got %+v", res)
+	}
+
+	found := &datastore.JobPosting{Search: "SRE remote"}
+	other := &datastore.JobPosting{Search: "removed search"}
+
+	if r := l.pickResume(found); r == nil || r.Name != "sre.pdf" {
+		t.Fatalf("expected the resume of the search, got %+v", r)
+	}
+	if r := l.pickResume(other); r == nil || r.Name != "backend.pdf" {
+		t.Fatalf("expected the default resume, got %+v", r)
+	}
+
+	if value, _ := l.settings(found).answers.Lookup("Notice period?"); value != "1 month" {
+		t.Fatalf("expected the answer of the search, got %q", value)
+	}
+	if value, _ := l.settings(other).answers.Lookup("Notice period?"); value != "3 months" {
+		t.Fatalf("expected the global answer, got %q", value)
+	}
+
+	if _, err := New(config.Linkedin{Searches: []config.Search{{Resume: "missing.pdf"}}}, bank, nil); err == nil {
+		t.Fatal("expected an unknown resume to fail")
+	}
+}
+
+func TestListUrl(t *testing.T) {
+	l := &Linkedin{config: config.Linkedin{PostingFilters: config.PostingFilters{MaxAgeDays: 2}}}
+
+	u, err := url.Parse(searchBaseUrl + "?keywords=golang")
+	if err != nil {
+		t.Fatalf("failed to parse url: %v", err)
+	}
+
+	query := mustParse(t, l.listUrl(u, 50)).Query()
+	if query.Get("start") != "50" || query.Get("f_AL") != "true" || query.Has("f_TPR") {
+		t.Fatalf("unexpected list url query %v", query)
+	}
+
+	// MaxAgeDays only becomes a date posted filter with SearchMaxAge
+	l.config.SearchMaxAge = true
+	if got := mustParse(t, l.listUrl(mustParse(t, searchBaseUrl+"?keywords=golang"), 0)).Query().Get("f_TPR"); got != "r172800" {
+		t.Fatalf("expected a date posted filter of MaxAgeDays, got %q", got)
+	}
+
+	// A date posted filter of the search wins over MaxAgeDays
+	u, err = url.Parse(searchBaseUrl + "?keywords=golang&f_TPR=r86400")
+	if err != nil {
+		t.Fatalf("failed to parse url: %v", err)
+	}
+	if got := mustParse(t, l.listUrl(u, 0)).Query().Get("f_TPR"); got != "r86400" {
+		t.Fatalf("expected the search date posted filter to be kept, got %q", got)
+	}
+
+	// Jobs applied to on the company site are only searched for with ExternalApply
+	l.config.ExternalApply = true
+	if mustParse(t, l.listUrl(mustParse(t, searchBaseUrl+"?keywords=golang"), 0)).Query().Has("f_AL") {
+		t.Fatal("expected the easy apply filter to be left out")
+	}
+}
+
+func mustParse(t *testing.T, u string) *url.URL {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("failed to parse url %q: %v", u, err)
+	}
+	return parsed
+}
@@ -0,0 +1,112 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+var (
+	ErrDailyLimitReached   = errors.New("daily application limit reached")
+	ErrCompanyLimitReached = errors.New("company application limit reached")
+	ErrSearchLimitReached  = errors.New("search application limit reached")
+)
+
+// Found is called with every job posting a search queues.
+type Found func(ctx context.Context, post *datastore.JobPosting) error
+
+// Platform is a job board the bot searches and applies on. Job postings of
+// every platform share the datastore, and the Runner drives the platform.
+type Platform interface {
+	// Name is the name the platform is registered with, also stored on its job postings
+	Name() string
+
+	// Limits returns the application limits configured for the platform
+	Limits() Limits
+
+	// Login signs in to the job board, if needed
+	Login(ctx context.Context) error
+
+	// Search crawls the configured searches and stores the job postings found.
+	// Eligible postings are queued and passed to found while still open.
+	Search(ctx context.Context, found Found) error
+
+	// FetchDetails opens the page of a stored job posting, ready for Apply
+	FetchDetails(ctx context.Context, post *datastore.JobPosting) error
+
+	// Apply applies for the open job posting and returns the status it moves to.
+	// It returns datastore.StatusSkipped with ErrSearchLimitReached when the
+	// daily limit of the search that found the posting is reached
+	Apply(ctx context.Context, post *datastore.JobPosting) (string, error)
+}
+
+// Limits are the daily application limits of a platform. A limit of 0
+// disables the check.
+type Limits struct {
+	// MaxApplications is checked against the applications sent on the platform
+	MaxApplications           int
+	MaxApplicationsPerCompany int
+}
+
+// Factory creates a platform from the config.
+type Factory func(cfg config.Config, bank *answers.Bank, ds datastore.Datastore) (Platform, error)
+
+var factories = map[string]Factory{}
+
+// Register makes a platform available by name. Platforms register
+// themselves from an init function. It panics when the name is taken.
+func Register(name string, factory Factory) {
+	if _, ok := factories[name]; ok {
+		panic("platform: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// New creates the platform registered with the name.
+func New(name string, cfg config.Config, bank *answers.Bank, ds datastore.Datastore) (Platform, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown platform %q, expected one of %v", name, Names())
+	}
+
+	return factory(cfg, bank, ds)
+}
+
+// Names returns the names of the registered platforms, sorted.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
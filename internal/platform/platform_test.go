@@ -0,0 +1,310 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/k1ng440/job-bot/internal/answers"
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/datastore"
+)
+
+// fakePlatform submits every application and records the jobs applied for.
+type fakePlatform struct {
+	limits Limits
+	queue  []*datastore.JobPosting
+	// closed are the jobs that no longer open
+	closed map[string]bool
+	// outcomes are the statuses applications end in, submitted by default
+	outcomes map[string]string
+	applied  []string
+}
+
+func (f *fakePlatform) Name() string                    { return "fake" }
+func (f *fakePlatform) Limits() Limits                  { return f.limits }
+func (f *fakePlatform) Login(ctx context.Context) error { return nil }
+
+func (f *fakePlatform) Search(ctx context.Context, found Found) error {
+	for _, post := range f.queue {
+		if err := found(ctx, post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakePlatform) FetchDetails(ctx context.Context, post *datastore.JobPosting) error {
+	if f.closed[post.ID] {
+		return fmt.Errorf("job %s is closed", post.ID)
+	}
+	return nil
+}
+
+func (f *fakePlatform) Apply(ctx context.Context, post *datastore.JobPosting) (string, error) {
+	f.applied = append(f.applied, post.ID)
+	if status, ok := f.outcomes[post.ID]; ok {
+		return status, nil
+	}
+	return datastore.StatusSubmitted, nil
+}
+
+// pagedPlatform searches a single results page, like linkedin, and
+// navigates away from it to apply for jobs with an apply url.
+type pagedPlatform struct {
+	fakePlatform
+	page string
+}
+
+func (p *pagedPlatform) Search(ctx context.Context, found Found) error {
+	p.page = "results"
+	for _, post := range p.queue {
+		if p.page != "results" {
+			return fmt.Errorf("results page left for %s", p.page)
+		}
+		if err := found(ctx, post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pagedPlatform) FetchDetails(ctx context.Context, post *datastore.JobPosting) error {
+	p.page = post.ID
+	return nil
+}
+
+func (p *pagedPlatform) Apply(ctx context.Context, post *datastore.JobPosting) (string, error) {
+	if post.ApplyUrl != "" {
+		p.page = post.ApplyUrl
+	}
+	return p.fakePlatform.Apply(ctx, post)
+}
+
+func TestRunnerApply(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.NewMemoryDatastore()
+
+	for _, post := range []*datastore.JobPosting{
+		{Platform: "fake", ID: "acme-1", Company: "Acme", Score: 4},
+		{Platform: "fake", ID: "acme-2", Company: "Acme", Score: 3},
+		{Platform: "fake", ID: "other-1", Company: "Other", Score: 2},
+		{Platform: "fake", ID: "other-2", Company: "Other", Score: 1},
+		{Platform: "board", ID: "board-1", Company: "Board", Score: 5},
+	} {
+		if err := ds.InsertJobPosting(ctx, post); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	// Another bot sharing the datastore is applying for this one right now
+	if err := ds.InsertJobPosting(ctx, &datastore.JobPosting{Platform: "fake", ID: "busy-1", Company: "Busy", Score: 6}); err != nil {
+		t.Fatalf("failed to insert job posting: %v", err)
+	}
+	if err := ds.SetJobPostingStatus(ctx, "fake", "busy-1", datastore.StatusApplying); err != nil {
+		t.Fatalf("failed to claim job posting: %v", err)
+	}
+
+	// Applications sent on other platforms do not count towards the daily limit
+	for i := 0; i < 2; i++ {
+		if err := ds.IncAppliedTodayCount(ctx, "board"); err != nil {
+			t.Fatalf("failed to increment applied count: %v", err)
+		}
+	}
+
+	p := &fakePlatform{limits: Limits{MaxApplications: 2, MaxApplicationsPerCompany: 1}}
+	if err := NewRunner(p, ds).Apply(ctx); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+
+	// The second Acme job hits the company limit, the last job the daily limit
+	if len(p.applied) != 2 || p.applied[0] != "acme-1" || p.applied[1] != "other-1" {
+		t.Fatalf("expected acme-1 and other-1 to be applied for, got %v", p.applied)
+	}
+
+	posts, err := ds.GetJobPostings(ctx)
+	if err != nil {
+		t.Fatalf("failed to get job postings: %v", err)
+	}
+
+	expected := map[string]string{
+		"acme-1":  datastore.StatusSubmitted,
+		"acme-2":  datastore.StatusSkipped,
+		"other-1": datastore.StatusSubmitted,
+		"other-2": datastore.StatusQueued,
+		"board-1": datastore.StatusQueued,
+		"busy-1":  datastore.StatusApplying,
+	}
+	for _, post := range posts {
+		if post.Status != expected[post.ID] {
+			t.Fatalf("expected %s to be %s, got %s", post.ID, expected[post.ID], post.Status)
+		}
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := &fakePlatform{}
+	done := make(chan error, 1)
+	go func() { done <- NewRunner(p, datastore.NewMemoryDatastore()).Run(ctx) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the run to stop waiting once the context is done")
+	}
+}
+
+func TestRunnerCollect(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.NewMemoryDatastore()
+
+	p := &fakePlatform{queue: []*datastore.JobPosting{
+		{Platform: "fake", ID: "low", Company: "Acme", Score: 1},
+		{Platform: "fake", ID: "closed", Company: "Acme", Score: 4},
+		{Platform: "fake", ID: "high", Company: "Other", Score: 3},
+		{Platform: "fake", ID: "question", Company: "Other", Score: 2},
+	}}
+	// Platforms store the jobs they find before passing them on
+	for _, post := range p.queue {
+		if err := ds.InsertJobPosting(ctx, post); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	r := NewRunner(p, ds)
+	if err := r.Collect(ctx); err != nil {
+		t.Fatalf("failed to collect: %v", err)
+	}
+	if len(p.applied) != 0 || r.summary.collected != 4 {
+		t.Fatalf("expected 4 jobs to be collected and none applied for, got %d and %v", r.summary.collected, p.applied)
+	}
+
+	counts, err := ds.GetStatusCounts(ctx)
+	if err != nil {
+		t.Fatalf("failed to get status counts: %v", err)
+	}
+	if counts[datastore.StatusQueued] != 4 {
+		t.Fatalf("expected every job to stay queued, got %v", counts)
+	}
+
+	// The queue is drained best scoring first, and every job taken from it
+	// ends in a status so it is not picked again
+	p.closed = map[string]bool{"closed": true}
+	p.outcomes = map[string]string{"question": datastore.StatusNeedsAnswer}
+	r = NewRunner(p, ds)
+	if err := r.Apply(ctx); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+
+	if len(p.applied) != 3 || p.applied[0] != "high" || p.applied[1] != "question" || p.applied[2] != "low" {
+		t.Fatalf("expected high, question and low to be applied for in order, got %v", p.applied)
+	}
+
+	posts, err := ds.GetJobPostings(ctx)
+	if err != nil {
+		t.Fatalf("failed to get job postings: %v", err)
+	}
+	expected := map[string]string{
+		"low":      datastore.StatusSubmitted,
+		"closed":   datastore.StatusFailed,
+		"high":     datastore.StatusSubmitted,
+		"question": datastore.StatusNeedsAnswer,
+	}
+	for _, post := range posts {
+		if post.Status != expected[post.ID] {
+			t.Fatalf("expected %s to be %s, got %s", post.ID, expected[post.ID], post.Status)
+		}
+	}
+	if r.summary.outcomes[datastore.StatusSubmitted] != 2 || r.summary.outcomes[datastore.StatusNeedsAnswer] != 1 {
+		t.Fatalf("unexpected run summary %+v", r.summary)
+	}
+
+	count, err := ds.GetAppliedTodayCount(ctx, "fake")
+	if err != nil {
+		t.Fatalf("failed to get applied count: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected only the submitted applications to be counted, got %d", count)
+	}
+}
+
+func TestRunnerSearch(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.NewMemoryDatastore()
+
+	p := &pagedPlatform{fakePlatform: fakePlatform{queue: []*datastore.JobPosting{
+		{Platform: "fake", ID: "easy-1", Company: "Acme"},
+		{Platform: "fake", ID: "ats-1", Company: "Acme", ApplyUrl: "https://boards.greenhouse.io/acme/jobs/1"},
+		{Platform: "fake", ID: "easy-2", Company: "Other"},
+	}}}
+	for _, post := range p.queue {
+		if err := ds.InsertJobPosting(ctx, post); err != nil {
+			t.Fatalf("failed to insert job posting: %v", err)
+		}
+	}
+
+	if err := NewRunner(p, ds).search(ctx); err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+
+	// The job applied for on the ATS waits until the results page is done
+	if len(p.applied) != 3 || p.applied[0] != "easy-1" || p.applied[1] != "easy-2" || p.applied[2] != "ats-1" {
+		t.Fatalf("expected ats-1 to be applied for last, got %v", p.applied)
+	}
+
+	counts, err := ds.GetStatusCounts(ctx)
+	if err != nil {
+		t.Fatalf("failed to get status counts: %v", err)
+	}
+	if counts[datastore.StatusSubmitted] != 3 {
+		t.Fatalf("expected every job to be submitted, got %v", counts)
+	}
+}
+
+func TestNew(t *testing.T) {
+	Register("fake", func(config.Config, *answers.Bank, datastore.Datastore) (Platform, error) {
+		return &fakePlatform{}, nil
+	})
+
+	p, err := New("fake", config.Config{}, nil, datastore.NewMemoryDatastore())
+	if err != nil {
+		t.Fatalf("failed to create platform: %v", err)
+	}
+	if p.Name() != "fake" {
+		t.Fatalf("expected the fake platform, got %s", p.Name())
+	}
+
+	if _, err := New("unknown", config.Config{}, nil, nil); err == nil {
+		t.Fatalf("expected an unknown platform to fail")
+	}
+}
@@ -0,0 +1,373 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/k1ng440/job-bot/internal/datastore"
+	"github.com/rs/zerolog/log"
+)
+
+// staleApplyingAfter is how long a job may be applied for before it is taken
+// to be left behind by a bot that stopped mid-way, and is queued again.
+const staleApplyingAfter = time.Hour
+
+// Runner runs the search and apply pipeline of a platform, keeping track of
+// the job postings and the application limits in the datastore.
+type Runner struct {
+	platform Platform
+	ds       datastore.Datastore
+	summary  summary
+}
+
+// summary counts the outcomes of a run.
+type summary struct {
+	// outcomes counts the statuses applications ended in
+	outcomes map[string]int
+	// skipped counts the jobs skipped by the company and search limits
+	skipped   int
+	collected int
+}
+
+func (s *summary) log(platform string) {
+	log.Info().
+		Str("platform", platform).
+		Int("collected", s.collected).
+		Int("submitted", s.outcomes[datastore.StatusSubmitted]).
+		Int("needs_answer", s.outcomes[datastore.StatusNeedsAnswer]).
+		Int("needs_human", s.outcomes[datastore.StatusNeedsHuman]).
+		Int("abandoned", s.outcomes[datastore.StatusFailed]).
+		Int("skipped_by_limit", s.skipped).
+		Msg("Run summary")
+}
+
+func NewRunner(p Platform, ds datastore.Datastore) *Runner {
+	return &Runner{
+		platform: p,
+		ds:       ds,
+		summary:  summary{outcomes: map[string]int{}},
+	}
+}
+
+// Run searches every search and applies for the eligible jobs as they
+// are found.
+func (r *Runner) Run(ctx context.Context) error {
+	if err := r.login(ctx); err != nil {
+		return err
+	}
+
+	defer r.summary.log(r.platform.Name())
+
+	if err := r.retry(ctx); err != nil {
+		return r.stop(err)
+	}
+
+	if err := r.search(ctx); err != nil {
+		return r.stop(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(10 * time.Second):
+	}
+	return nil
+}
+
+// search applies for the eligible jobs as the searches find them. Jobs
+// applied for on another site, such as a company's ATS, leave the page the
+// search is reading, so they are applied for once the searches are done.
+func (r *Runner) search(ctx context.Context) error {
+	var offsite []*datastore.JobPosting
+	if err := r.platform.Search(ctx, func(ctx context.Context, post *datastore.JobPosting) error {
+		if post.ApplyUrl != "" {
+			offsite = append(offsite, post)
+			return nil
+		}
+
+		claimed, err := r.claim(ctx, post)
+		if err != nil || !claimed {
+			return err
+		}
+		return r.applyAndRecord(ctx, post)
+	}); err != nil {
+		return err
+	}
+
+	for _, post := range offsite {
+		if err := r.openAndApply(ctx, post); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Collect searches every search and stores the eligible jobs in the
+// queue without applying for them. The queue is applied to by Apply.
+func (r *Runner) Collect(ctx context.Context) error {
+	if err := r.login(ctx); err != nil {
+		return err
+	}
+
+	defer r.summary.log(r.platform.Name())
+
+	return r.platform.Search(ctx, func(_ context.Context, post *datastore.JobPosting) error {
+		log.Info().Str("title", post.Title).Float64("score", post.Score).Msg("Job queued")
+		r.summary.collected++
+		return nil
+	})
+}
+
+// Apply applies for the queued jobs of the platform, best scoring first,
+// until the queue is empty or the daily limit is reached.
+func (r *Runner) Apply(ctx context.Context) error {
+	if err := r.login(ctx); err != nil {
+		return err
+	}
+
+	defer r.summary.log(r.platform.Name())
+
+	// Company and search limits are daily, so skipped jobs get another chance
+	if _, err := r.ds.RequeueJobPostings(ctx, datastore.StatusSkipped); err != nil {
+		return fmt.Errorf("failed to requeue skipped job postings. %w", err)
+	}
+
+	// So do jobs left behind by an interrupted run, but not the ones
+	// other bots sharing the datastore are applying for right now
+	if _, err := r.ds.RequeueStaleJobPostings(ctx, datastore.StatusApplying, time.Now().Add(-staleApplyingAfter)); err != nil {
+		return fmt.Errorf("failed to requeue stale job postings. %w", err)
+	}
+
+	if err := r.retry(ctx); err != nil {
+		return r.stop(err)
+	}
+
+	if err := r.drain(ctx); err != nil {
+		return r.stop(err)
+	}
+
+	return nil
+}
+
+func (r *Runner) login(ctx context.Context) error {
+	if err := r.platform.Login(ctx); err != nil {
+		log.Error().Err(err).Str("platform", r.platform.Name()).Msg("Failed to login")
+		return err
+	}
+
+	return nil
+}
+
+// stop ends the run cleanly when the daily limit is reached.
+func (r *Runner) stop(err error) error {
+	if errors.Is(err, ErrDailyLimitReached) {
+		log.Info().Int("max_applications", r.platform.Limits().MaxApplications).Msg("Daily application limit reached. Stopping")
+		return nil
+	}
+
+	return err
+}
+
+// retry applies again to the jobs whose questions have all been answered
+// since the last attempt.
+func (r *Runner) retry(ctx context.Context) error {
+	posts, err := r.ds.GetRetryableJobPostings(ctx, r.platform.Name())
+	if err != nil {
+		return fmt.Errorf("failed to get job postings to retry. %w", err)
+	}
+
+	for _, post := range posts {
+		log.Info().Str("title", post.Title).Msg("Retrying job with answered questions")
+		claimed, err := r.claim(ctx, post)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := r.platform.FetchDetails(ctx, post); err != nil {
+			log.Warn().Err(err).Str("title", post.Title).Msg("Failed to open job")
+			if err := r.release(ctx, post); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.applyAndRecord(ctx, post); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drain applies for the queued jobs until the queue is empty. Every job
+// taken from the queue gets a status, so it is not picked again.
+func (r *Runner) drain(ctx context.Context) error {
+	for {
+		post, err := r.ds.GetUnappliedJobPosting(ctx, r.platform.Name())
+		if err != nil {
+			return fmt.Errorf("failed to get queued job posting. %w", err)
+		}
+		if post == nil {
+			log.Info().Msg("Job queue is empty")
+			return nil
+		}
+
+		if err := r.openAndApply(ctx, post); err != nil {
+			return err
+		}
+	}
+}
+
+// openAndApply claims the job, opens it and applies for it. Jobs that no
+// longer open are marked failed.
+func (r *Runner) openAndApply(ctx context.Context, post *datastore.JobPosting) error {
+	claimed, err := r.claim(ctx, post)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := r.platform.FetchDetails(ctx, post); err != nil {
+		log.Warn().Err(err).Str("title", post.Title).Msg("Failed to open job")
+		if err := r.ds.SetJobPostingStatus(ctx, post.Platform, post.ID, datastore.StatusFailed); err != nil {
+			return fmt.Errorf("failed to update job posting status. %w", err)
+		}
+		return nil
+	}
+
+	return r.applyAndRecord(ctx, post)
+}
+
+// claim moves the job to applying so no other bot sharing the datastore
+// applies for it. It reports false when another bot got to it first.
+func (r *Runner) claim(ctx context.Context, post *datastore.JobPosting) (bool, error) {
+	err := r.ds.SetJobPostingStatus(ctx, post.Platform, post.ID, datastore.StatusApplying)
+	if errors.Is(err, datastore.ErrTaken) {
+		log.Info().Str("title", post.Title).Msg("Job is being applied for by another bot. Skipping")
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update job posting status. %w", err)
+	}
+
+	return true, nil
+}
+
+// release gives up a claimed job, moving it back to the status it had.
+func (r *Runner) release(ctx context.Context, post *datastore.JobPosting) error {
+	if err := r.ds.SetJobPostingStatus(ctx, post.Platform, post.ID, post.Status); err != nil {
+		return fmt.Errorf("failed to update job posting status. %w", err)
+	}
+
+	return nil
+}
+
+// applyAndRecord applies for the claimed job and stores the outcome on the
+// posting. Only submitted applications count towards the limits.
+func (r *Runner) applyAndRecord(ctx context.Context, post *datastore.JobPosting) error {
+	if err := r.checkQuota(ctx, post); err != nil {
+		if !errors.Is(err, ErrCompanyLimitReached) {
+			// The job is left for the next run
+			if err := r.release(ctx, post); err != nil {
+				log.Warn().Err(err).Str("title", post.Title).Msg("Failed to release job")
+			}
+			return err
+		}
+
+		log.Info().Str("title", post.Title).Str("company", post.Company).Msg("Company application limit reached. Skipping")
+		r.summary.skipped++
+		return r.ds.SetJobPostingStatus(ctx, post.Platform, post.ID, datastore.StatusSkipped)
+	}
+
+	status, err := r.platform.Apply(ctx, post)
+	switch {
+	case errors.Is(err, ErrSearchLimitReached):
+		log.Info().Str("title", post.Title).Str("search", post.Search).Msg("Search application limit reached. Skipping")
+		r.summary.skipped++
+	case err != nil:
+		log.Warn().Err(err).Str("title", post.Title).Msg("Failed to apply for job")
+		fallthrough
+	default:
+		log.Info().Str("title", post.Title).Str("outcome", status).Msg("Application finished")
+		r.summary.outcomes[status]++
+	}
+
+	if err := r.ds.SetJobPostingStatus(ctx, post.Platform, post.ID, status); err != nil {
+		return fmt.Errorf("failed to update job posting status. %w", err)
+	}
+
+	if status == datastore.StatusSubmitted {
+		return r.recordSubmission(ctx, post)
+	}
+
+	return nil
+}
+
+// checkQuota returns ErrDailyLimitReached once MaxApplications applications
+// were sent on the platform today, and ErrCompanyLimitReached once MaxApplicationsPerCompany
+// applications were sent to the company of the posting today.
+func (r *Runner) checkQuota(ctx context.Context, post *datastore.JobPosting) error {
+	limits := r.platform.Limits()
+
+	if limits.MaxApplications > 0 {
+		count, err := r.ds.GetAppliedTodayCount(ctx, r.platform.Name())
+		if err != nil {
+			return fmt.Errorf("failed to get applied count. %w", err)
+		}
+		if count >= limits.MaxApplications {
+			return ErrDailyLimitReached
+		}
+	}
+
+	if limits.MaxApplicationsPerCompany > 0 {
+		count, err := r.ds.GetAppliedCountByCompany(ctx, post.Company)
+		if err != nil {
+			return fmt.Errorf("failed to get applied count by company. %w", err)
+		}
+		if count >= limits.MaxApplicationsPerCompany {
+			return ErrCompanyLimitReached
+		}
+	}
+
+	return nil
+}
+
+// recordSubmission counts a submitted application towards the limits.
+func (r *Runner) recordSubmission(ctx context.Context, post *datastore.JobPosting) error {
+	if err := r.ds.IncAppliedTodayCount(ctx, post.Platform); err != nil {
+		return fmt.Errorf("failed to increment applied count. %w", err)
+	}
+
+	if err := r.ds.IncAppliedCountByCompany(ctx, post.Company); err != nil {
+		return fmt.Errorf("failed to increment applied count by company. %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package scoring
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/k1ng440/job-bot/internal/config"
+)
+
+// Fields that rules can match against.
+const (
+	FieldTitle       = "title"
+	FieldCompany     = "company"
+	FieldDescription = "description"
+	FieldLocation    = "location"
+	FieldSeniority   = "seniority"
+)
+
+// Job is the view of a posting that rules are matched against.
+type Job struct {
+	Title       string
+	Company     string
+	Description string
+	Location    string
+	Seniority   string
+}
+
+func (j Job) field(name string) string {
+	switch name {
+	case FieldTitle:
+		return j.Title
+	case FieldCompany:
+		return j.Company
+	case FieldDescription:
+		return j.Description
+	case FieldLocation:
+		return j.Location
+	case FieldSeniority:
+		return j.Seniority
+	default:
+		return ""
+	}
+}
+
+// Contribution is the weight a matching rule added to the score.
+type Contribution struct {
+	Rule   string
+	Weight float64
+}
+
+// Result is the score of a posting along with the rules that made it up.
+type Result struct {
+	Score         float64
+	Contributions []Contribution
+}
+
+type rule struct {
+	field   string
+	pattern *regexp.Regexp
+	weight  float64
+}
+
+func (r rule) String() string {
+	return r.field + ": " + r.pattern.String()
+}
+
+// Scorer scores postings against the configured rules.
+type Scorer struct {
+	rules    []rule
+	minScore float64
+}
+
+// New compiles the scoring rules.
+func New(cfg config.Scoring) (*Scorer, error) {
+	s := &Scorer{rules: make([]rule, 0, len(cfg.Rules)), minScore: cfg.MinScore}
+	for _, r := range cfg.Rules {
+		switch r.Field {
+		case FieldTitle, FieldCompany, FieldDescription, FieldLocation, FieldSeniority:
+		default:
+			return nil, fmt.Errorf("invalid scoring field %q", r.Field)
+		}
+
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scoring pattern %q. %w", r.Pattern, err)
+		}
+
+		s.rules = append(s.rules, rule{field: r.Field, pattern: re, weight: r.Weight})
+	}
+
+	return s, nil
+}
+
+// Score adds up the weights of every rule matching the job.
+// Each rule counts once no matter how often it matches.
+func (s *Scorer) Score(job Job) Result {
+	var res Result
+	for _, r := range s.rules {
+		if r.pattern.MatchString(job.field(r.field)) {
+			res.Score += r.weight
+			res.Contributions = append(res.Contributions, Contribution{Rule: r.String(), Weight: r.weight})
+		}
+	}
+
+	return res
+}
+
+// Accept reports whether the score is high enough to apply.
+// Without rules every posting is accepted.
+func (s *Scorer) Accept(res Result) bool {
+	return len(s.rules) == 0 || res.Score >= s.minScore
+}
+
+// MinScore returns the minimum score to apply.
+func (s *Scorer) MinScore() float64 {
+	return s.minScore
+}
@@ -0,0 +1,74 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package scoring_test
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/config"
+	"github.com/k1ng440/job-bot/internal/scoring"
+)
+
+func TestScore(t *testing.T) {
+	scorer, err := scoring.New(config.Scoring{
+		MinScore: 5,
+		Rules: []config.ScoreRule{
+			{Field: "title", Pattern: `(?i)\bgo(lang)?\b`, Weight: 5},
+			{Field: "description", Pattern: `(?i)kubernetes`, Weight: 2},
+			{Field: "location", Pattern: `(?i)remote`, Weight: 3},
+			{Field: "seniority", Pattern: `(?i)director`, Weight: -10},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create scorer: %v", err)
+	}
+
+	res := scorer.Score(scoring.Job{
+		Title:       "Senior Golang Engineer",
+		Description: "Kubernetes, kubernetes and more kubernetes",
+		Location:    "Berlin (Hybrid)",
+		Seniority:   "Mid-Senior level",
+	})
+	if res.Score != 7 {
+		t.Fatalf("expected score 7, got %v", res.Score)
+	}
+	if len(res.Contributions) != 2 {
+		t.Fatalf("expected 2 contributions, got %d", len(res.Contributions))
+	}
+	if !scorer.Accept(res) {
+		t.Fatal("expected the posting to be accepted")
+	}
+
+	res = scorer.Score(scoring.Job{Title: "Go Engineering Director", Seniority: "Director"})
+	if res.Score != -5 || scorer.Accept(res) {
+		t.Fatalf("expected score -5 to be rejected, got %v", res.Score)
+	}
+}
+
+func TestNewInvalidField(t *testing.T) {
+	_, err := scoring.New(config.Scoring{Rules: []config.ScoreRule{{Field: "salary", Pattern: "x"}}})
+	if err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
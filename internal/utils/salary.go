@@ -0,0 +1,158 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SalaryPeriod is the time span a salary amount is paid for.
+type SalaryPeriod string
+
+const (
+	PerHour  SalaryPeriod = "hour"
+	PerDay   SalaryPeriod = "day"
+	PerWeek  SalaryPeriod = "week"
+	PerMonth SalaryPeriod = "month"
+	PerYear  SalaryPeriod = "year"
+)
+
+// periodsPerYear converts an amount paid per period to a yearly amount,
+// assuming a 40 hour, 5 day working week.
+var periodsPerYear = map[SalaryPeriod]float64{
+	PerHour:  2080,
+	PerDay:   260,
+	PerWeek:  52,
+	PerMonth: 12,
+	PerYear:  1,
+}
+
+var (
+	salaryAmountRegex   = regexp.MustCompile(`(\d(?:[\d,.]*\d)?)\s*([kK])?`)
+	salaryCurrencyRegex = regexp.MustCompile(`(?i)\b(USD|EUR|GBP|CAD|AUD|CHF|INR|JPY)\b|CA\$|A\$|[$€£¥₹]`)
+	salaryPeriodRegex   = regexp.MustCompile(`(?i)/\s*(yr|year|hr|hour|mo|month|wk|week|day)\b|\b(?:a|an|per)\s+(year|hour|month|week|day)\b|\b(annually|yearly|hourly|monthly|weekly|daily)\b`)
+	salaryDecimalRegex  = regexp.MustCompile(`^(.*)\.(\d{1,2})$`)
+)
+
+var currencySymbols = map[string]string{
+	"$":   "USD",
+	"ca$": "CAD",
+	"a$":  "AUD",
+	"€":   "EUR",
+	"£":   "GBP",
+	"¥":   "JPY",
+	"₹":   "INR",
+}
+
+var periodNames = map[string]SalaryPeriod{
+	"hr": PerHour, "hour": PerHour, "hourly": PerHour,
+	"day": PerDay, "daily": PerDay,
+	"wk": PerWeek, "week": PerWeek, "weekly": PerWeek,
+	"mo": PerMonth, "month": PerMonth, "monthly": PerMonth,
+	"yr": PerYear, "year": PerYear, "yearly": PerYear, "annually": PerYear,
+}
+
+// Salary is a salary range as shown on a job posting.
+type Salary struct {
+	Min      float64
+	Max      float64
+	Currency string // ISO 4217 code, or empty when not shown
+	Period   SalaryPeriod
+}
+
+// Annual returns the salary range converted to a yearly amount.
+func (s Salary) Annual() (min, max float64) {
+	n := periodsPerYear[s.Period]
+	return s.Min * n, s.Max * n
+}
+
+// ParseSalary reads a salary such as "$120K/yr - $150K/yr",
+// "€60,000 - €75,000 a year" or "$45.50/hr". A single amount is returned
+// as both Min and Max, and a salary without a period is taken to be yearly.
+func ParseSalary(text string) (Salary, bool) {
+	var amounts []float64
+	for _, m := range salaryAmountRegex.FindAllStringSubmatch(text, 2) {
+		amount, err := parseAmount(m[1])
+		if err != nil {
+			continue
+		}
+		if m[2] != "" {
+			amount *= 1000
+		}
+		amounts = append(amounts, amount)
+	}
+	if len(amounts) == 0 {
+		return Salary{}, false
+	}
+
+	s := Salary{Min: amounts[0], Max: amounts[len(amounts)-1], Period: PerYear}
+	if s.Min > s.Max {
+		s.Min, s.Max = s.Max, s.Min
+	}
+
+	if m := salaryCurrencyRegex.FindString(text); m != "" {
+		if code, ok := currencySymbols[strings.ToLower(m)]; ok {
+			s.Currency = code
+		} else {
+			s.Currency = strings.ToUpper(m)
+		}
+	}
+
+	if m := salaryPeriodRegex.FindStringSubmatch(text); m != nil {
+		for _, name := range m[1:] {
+			if p, ok := periodNames[strings.ToLower(name)]; ok {
+				s.Period = p
+				break
+			}
+		}
+	}
+
+	return s, true
+}
+
+// parseAmount parses a number with thousands separators and up to two
+// decimals, such as "60,000", "60.000" or "45.50".
+func parseAmount(text string) (float64, error) {
+	var cents float64
+	if m := salaryDecimalRegex.FindStringSubmatch(text); m != nil {
+		c, err := strconv.Atoi(m[2])
+		if err != nil {
+			return 0, err
+		}
+		cents = float64(c)
+		if len(m[2]) == 1 {
+			cents *= 10
+		}
+		text = m[1]
+	}
+
+	n, err := ParseStringToInt(text)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(n) + cents/100, nil
+}
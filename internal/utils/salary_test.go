@@ -0,0 +1,63 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/k1ng440/job-bot/internal/utils"
+)
+
+func TestParseSalary(t *testing.T) {
+	tests := map[string]utils.Salary{
+		"$120K/yr - $150K/yr":        {Min: 120000, Max: 150000, Currency: "USD", Period: utils.PerYear},
+		"€60,000 - €75,000 a year":   {Min: 60000, Max: 75000, Currency: "EUR", Period: utils.PerYear},
+		"$45.50/hr - $60/hr":         {Min: 45.5, Max: 60, Currency: "USD", Period: utils.PerHour},
+		"£4,000 per month":           {Min: 4000, Max: 4000, Currency: "GBP", Period: utils.PerMonth},
+		"CA$90K - CA$110K":           {Min: 90000, Max: 110000, Currency: "CAD", Period: utils.PerYear},
+		"70.000 - 85.000 EUR yearly": {Min: 70000, Max: 85000, Currency: "EUR", Period: utils.PerYear},
+	}
+
+	for text, want := range tests {
+		got, ok := utils.ParseSalary(text)
+		if !ok {
+			t.Fatalf("failed to parse %q", text)
+		}
+		if got != want {
+			t.Fatalf("ParseSalary(%q) = %+v, want %+v", text, got, want)
+		}
+	}
+
+	if _, ok := utils.ParseSalary("Remote · Full-time"); ok {
+		t.Fatal("expected text without an amount to not parse")
+	}
+}
+
+func TestSalaryAnnual(t *testing.T) {
+	s := utils.Salary{Min: 40, Max: 50, Currency: "USD", Period: utils.PerHour}
+	min, max := s.Annual()
+	if min != 83200 || max != 104000 {
+		t.Fatalf("Annual() = %g, %g, want 83200, 104000", min, max)
+	}
+}
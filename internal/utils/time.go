@@ -0,0 +1,82 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	postedAgoRegex = regexp.MustCompile(`(?i)(\d+|an?)(\+)?\s+(second|minute|hour|day|week|month|year)s?\s+ago`)
+	// postedNowRegex only matches a whole "·" separated segment, so company
+	// names such as "USA TODAY" are not taken for the posted time
+	postedNowRegex = regexp.MustCompile(`(?i)^(?:(?:re)?posted\s+)?\b(just now|just posted|today)\b$`)
+)
+
+// ParsePostedAgo finds a relative time such as "Reposted 3 weeks ago" or
+// "Posted 30+ days ago" in the text and returns the time it refers to.
+// Months and years are approximated as 30 and 365 days, and "30+ days" is
+// taken as 31 days so it is older than 30 days.
+func ParsePostedAgo(text string, now time.Time) (time.Time, bool) {
+	match := postedAgoRegex.FindStringSubmatch(text)
+	if match == nil {
+		for _, part := range strings.Split(text, "·") {
+			if postedNowRegex.MatchString(strings.TrimSpace(part)) {
+				return now, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	n := 1
+	if c, err := strconv.Atoi(match[1]); err == nil {
+		n = c
+	}
+	if match[2] == "+" {
+		n++
+	}
+
+	var unit time.Duration
+	switch strings.ToLower(match[3]) {
+	case "second":
+		unit = time.Second
+	case "minute":
+		unit = time.Minute
+	case "hour":
+		unit = time.Hour
+	case "day":
+		unit = 24 * time.Hour
+	case "week":
+		unit = 7 * 24 * time.Hour
+	case "month":
+		unit = 30 * 24 * time.Hour
+	case "year":
+		unit = 365 * 24 * time.Hour
+	}
+
+	return now.Add(-time.Duration(n) * unit), true
+}
@@ -0,0 +1,63 @@
+/* MIT License
+
+Copyright (c) 2023 Asaduzzaman Pavel (contact@iampavel.dev)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k1ng440/job-bot/internal/utils"
+)
+
+func TestParsePostedAgo(t *testing.T) {
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"Acme · Berlin, Germany 2 days ago · 42 applicants": now.AddDate(0, 0, -2),
+		"Reposted 3 weeks ago":                              now.AddDate(0, 0, -21),
+		"an hour ago":                                       now.Add(-time.Hour),
+		"Posted 1 month ago":                                now.AddDate(0, 0, -30),
+		"Just now":                                          now,
+		"Just posted":                                       now,
+		"Posted 30+ days ago":                               now.AddDate(0, 0, -31),
+		"Acme · Berlin, Germany · Today":                    now,
+		"USA TODAY · Berlin, Germany 3 weeks ago":           now.AddDate(0, 0, -21),
+	}
+
+	for text, want := range tests {
+		got, ok := utils.ParsePostedAgo(text, now)
+		if !ok {
+			t.Fatalf("failed to parse %q", text)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("ParsePostedAgo(%q) = %v, want %v", text, got, want)
+		}
+	}
+
+	for _, text := range []string{"Berlin, Germany", "Today Tix · Berlin, Germany · 42 applicants"} {
+		if _, ok := utils.ParsePostedAgo(text, now); ok {
+			t.Fatalf("expected %q without a relative time to not parse", text)
+		}
+	}
+}